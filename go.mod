@@ -3,16 +3,19 @@ module github.com/walmartdigital/go-kaya
 go 1.14
 
 require (
-	github.com/asaskevich/govalidator v0.0.0-20200428143746-21a406dcc535
+	github.com/go-logr/logr v0.1.0
+	github.com/go-logr/zapr v0.1.0
 	github.com/go-playground/universal-translator v0.17.0 // indirect
 	github.com/go-resty/resty/v2 v2.3.0
 	github.com/golang/mock v1.4.3
+	github.com/golang/protobuf v1.3.2 // indirect
 	github.com/leodido/go-urn v1.2.0 // indirect
 	github.com/onsi/ginkgo v1.11.0
 	github.com/onsi/gomega v1.8.1
 	github.com/spf13/pflag v1.0.5
 	go.uber.org/zap v1.15.0
+	gopkg.in/check.v1 v1.0.0-20190902080502-41f04d3bba15 // indirect
 	gopkg.in/go-playground/assert.v1 v1.2.1 // indirect
 	gopkg.in/go-playground/validator.v9 v9.31.0
-	sigs.k8s.io/controller-runtime v0.5.4
+	gopkg.in/yaml.v2 v2.2.7
 )
@@ -0,0 +1,222 @@
+// Package cli implements the kaya command line:
+//
+//	kaya connectors list -a localhost:8083
+//	kaya connectors get logging -a localhost:8083 -o yaml
+//	kaya connectors apply -f connectors/ -a localhost:8083
+//	kaya render -f es-sink.json --values prod.yaml
+//	kaya connectors migrate logging -a localhost:8083 --to localhost:9083 --stop
+//
+// Results are written to stdout in the format selected with -o, diagnostics and errors to
+// stderr. The exit code tells why a command failed, see the Exit constants.
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+	"github.com/walmartdigital/go-kaya/pkg/kafkaconnect"
+)
+
+// Exit codes returned by Run.
+const (
+	// ExitOK is returned when the command succeeded.
+	ExitOK = 0
+	// ExitError is returned when the command failed.
+	ExitError = 1
+	// ExitUsage is returned for unknown commands, flags or missing arguments.
+	ExitUsage = 2
+	// ExitNotFound is returned when a connector does not exist.
+	ExitNotFound = 3
+	// ExitConflict is returned when Kafka Connect rejects a request with a conflict,
+	// typically because a rebalance is in progress.
+	ExitConflict = 4
+	// ExitTimeout is returned when status --until times out.
+	ExitTimeout = 5
+)
+
+// exitError is an error carrying the exit code of the command.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func usageError(format string, args ...interface{}) error {
+	return &exitError{code: ExitUsage, err: fmt.Errorf(format, args...)}
+}
+
+// responseError converts the outcome of a client call into an error with the matching
+// exit code.
+func responseError(resp *kafkaconnect.Response, err error) error {
+	if err == nil {
+		return nil
+	}
+	code := ExitError
+	if resp != nil {
+		switch resp.Result {
+		case "notfound":
+			code = ExitNotFound
+		case "conflict":
+			code = ExitConflict
+		}
+	}
+	return &exitError{code: code, err: err}
+}
+
+func exitCode(err error) int {
+	var e *exitError
+	if errors.As(err, &e) {
+		return e.code
+	}
+	return ExitError
+}
+
+var commands = append(append(append([]command{}, connectorCommands...), migrateCommands...), configCommands...)
+
+// command is a leaf command of the CLI.
+type command struct {
+	path []string
+	// args describes the positional arguments in the usage line.
+	args  string
+	nargs int
+	short string
+	// flags registers the flags specific to the command.
+	flags func(fs *flag.FlagSet, o *options)
+	run   func(e *env, args []string) error
+}
+
+func (c command) name() string {
+	return strings.Join(c.path, " ")
+}
+
+func (c command) usage() string {
+	u := "kaya " + c.name()
+	if c.args != "" {
+		u += " " + c.args
+	}
+	return u + " [flags]"
+}
+
+// Run executes the command line args, without the program name, and returns the exit code.
+func Run(args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 {
+		printUsage(stderr, "")
+		return ExitUsage
+	}
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(stdout, "")
+		return ExitOK
+	}
+
+	cmd, rest := findCommand(args)
+	if cmd == nil {
+		if isGroup(args[0]) && (len(args) == 1 || strings.HasPrefix(args[1], "-")) {
+			printUsage(stderr, args[0])
+			return ExitUsage
+		}
+		fmt.Fprintf(stderr, "Error: unknown command %q\nRun 'kaya help' for usage.\n", strings.Join(leadingWords(args), " "))
+		return ExitUsage
+	}
+
+	o := &options{}
+	fs := flag.NewFlagSet(cmd.name(), flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	o.addGlobalFlags(fs)
+	if cmd.flags != nil {
+		cmd.flags(fs, o)
+	}
+	if err := fs.Parse(rest); err != nil {
+		if err == flag.ErrHelp {
+			printCommandUsage(stdout, cmd, fs)
+			return ExitOK
+		}
+		fmt.Fprintf(stderr, "Error: %s\n", err.Error())
+		printCommandUsage(stderr, cmd, fs)
+		return ExitUsage
+	}
+
+	positional := fs.Args()
+	if cmd.nargs >= 0 && len(positional) != cmd.nargs {
+		fmt.Fprintf(stderr, "Error: %q requires %d argument(s), got %d\n", cmd.name(), cmd.nargs, len(positional))
+		printCommandUsage(stderr, cmd, fs)
+		return ExitUsage
+	}
+
+	e, err := newEnv(o, stdout, stderr)
+	if err == nil {
+		err = cmd.run(e, positional)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %s\n", err.Error())
+		return exitCode(err)
+	}
+	return ExitOK
+}
+
+func findCommand(args []string) (*command, []string) {
+	var found *command
+	var rest []string
+	for i := range commands {
+		c := &commands[i]
+		if len(args) < len(c.path) {
+			continue
+		}
+		match := true
+		for j, word := range c.path {
+			match = match && args[j] == word
+		}
+		if match && (found == nil || len(c.path) > len(found.path)) {
+			found = c
+			rest = args[len(c.path):]
+		}
+	}
+	return found, rest
+}
+
+func isGroup(word string) bool {
+	for _, c := range commands {
+		if len(c.path) > 1 && c.path[0] == word {
+			return true
+		}
+	}
+	return false
+}
+
+func leadingWords(args []string) []string {
+	var words []string
+	for _, a := range args {
+		if strings.HasPrefix(a, "-") {
+			break
+		}
+		words = append(words, a)
+	}
+	return words
+}
+
+func printUsage(w io.Writer, group string) {
+	fmt.Fprintln(w, "Usage: kaya <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	sorted := append([]command{}, commands...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].name() < sorted[j].name() })
+	for _, c := range sorted {
+		if group != "" && c.path[0] != group {
+			continue
+		}
+		fmt.Fprintf(w, "  %-40s %s\n", strings.TrimSuffix(strings.TrimPrefix(c.usage(), "kaya "), " [flags]"), c.short)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'kaya <command> --help' for the flags of a command.")
+}
+
+func printCommandUsage(w io.Writer, c *command, fs *flag.FlagSet) {
+	fmt.Fprintf(w, "Usage: %s\n\n%s\n\nFlags:\n%s", c.usage(), c.short, fs.FlagUsages())
+}
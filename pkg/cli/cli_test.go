@@ -0,0 +1,396 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/walmartdigital/go-kaya/pkg/cli"
+	"github.com/walmartdigital/go-kaya/pkg/kafkaconnect"
+	"github.com/walmartdigital/go-kaya/pkg/kafkaconnect/kafkaconnecttest"
+)
+
+func TestAll(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "CLI")
+}
+
+type invocation struct {
+	code   int
+	stdout string
+	stderr string
+}
+
+func run(args ...string) invocation {
+	var stdout, stderr bytes.Buffer
+	code := cli.Run(args, &stdout, &stderr)
+	return invocation{code: code, stdout: stdout.String(), stderr: stderr.String()}
+}
+
+var configDir string
+
+var _ = BeforeSuite(func() {
+	var err error
+	configDir, err = ioutil.TempDir("", "kaya")
+	Expect(err).To(BeNil())
+	os.Setenv(cli.ConfigEnv, filepath.Join(configDir, "config"))
+})
+
+var _ = AfterSuite(func() {
+	os.RemoveAll(configDir)
+})
+
+var _ = Describe("kaya", func() {
+	var server *kafkaconnecttest.Server
+
+	BeforeEach(func() {
+		server = kafkaconnecttest.NewServer()
+		server.AddConnector(kafkaconnect.Connector{Name: "logging", Config: map[string]string{
+			"connector.class":     "io.confluent.connect.elasticsearch.ElasticsearchSinkConnector",
+			"tasks.max":           "2",
+			"topics":              "dumblogger-logs",
+			"connection.password": "s3cr3t",
+		}})
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should print usage and fail without a command", func() {
+		r := run()
+		Expect(r.code).To(Equal(cli.ExitUsage))
+		Expect(r.stdout).To(BeEmpty())
+		Expect(r.stderr).To(ContainSubstring("connectors get NAME"))
+	})
+
+	It("should print help on stdout", func() {
+		r := run("connectors", "get", "--help")
+		Expect(r.code).To(Equal(cli.ExitOK))
+		Expect(r.stdout).To(ContainSubstring("Usage: kaya connectors get NAME [flags]"))
+		Expect(r.stdout).To(ContainSubstring("--output"))
+	})
+
+	It("should reject unknown commands, flags and missing arguments", func() {
+		Expect(run("connectors", "frobnicate").code).To(Equal(cli.ExitUsage))
+		Expect(run("connectors", "list", "--frobnicate").code).To(Equal(cli.ExitUsage))
+		Expect(run("connectors", "get", "-a", server.URL).code).To(Equal(cli.ExitUsage))
+		Expect(run("connectors", "list", "-a", server.URL, "-o", "xml").code).To(Equal(cli.ExitUsage))
+		Expect(run("connectors", "list").stderr).To(ContainSubstring("--addr"))
+	})
+
+	It("should list connectors", func() {
+		r := run("connectors", "list", "-a", server.URL)
+		Expect(r.code).To(Equal(cli.ExitOK))
+		Expect(r.stdout).To(Equal("NAME\nlogging\n"))
+		Expect(r.stderr).To(BeEmpty())
+
+		r = run("connectors", "list", "-a", server.URL, "-o", "json")
+		Expect(r.stdout).To(MatchJSON(`["logging"]`))
+	})
+
+	It("should get a connector in every format with secrets redacted", func() {
+		r := run("connectors", "get", "logging", "-a", server.URL)
+		Expect(r.code).To(Equal(cli.ExitOK))
+		Expect(r.stdout).To(ContainSubstring("connection.password  [hidden]"))
+		Expect(r.stdout).To(HavePrefix("KEY"))
+
+		r = run("connectors", "get", "logging", "-a", server.URL, "-o", "json")
+		var connector kafkaconnect.Connector
+		Expect(json.Unmarshal([]byte(r.stdout), &connector)).To(Succeed())
+		Expect(connector.Name).To(Equal("logging"))
+		Expect(connector.Config["connection.password"]).To(Equal(kafkaconnect.RedactedValue))
+
+		r = run("connectors", "get", "logging", "-a", server.URL, "-o", "yaml")
+		Expect(r.stdout).To(ContainSubstring("name: logging\n"))
+		Expect(r.stdout).To(ContainSubstring("tasks.max: \"2\"\n"))
+
+		r = run("connectors", "get", "logging", "-a", server.URL, "-o", "name")
+		Expect(r.stdout).To(Equal("logging\n"))
+	})
+
+	It("should exit with a distinct code when a connector does not exist", func() {
+		r := run("connectors", "get", "missing", "-a", server.URL)
+		Expect(r.code).To(Equal(cli.ExitNotFound))
+		Expect(r.stdout).To(BeEmpty())
+		Expect(r.stderr).To(HavePrefix("Error: "))
+
+		Expect(run("connectors", "delete", "missing", "-a", server.URL).code).To(Equal(cli.ExitNotFound))
+		Expect(run("connectors", "restart", "missing", "-a", server.URL).code).To(Equal(cli.ExitNotFound))
+	})
+
+	It("should exit with a distinct code on conflicts", func() {
+		server.SetRebalancing(true)
+		r := run("connectors", "restart", "logging", "-a", server.URL)
+		Expect(r.code).To(Equal(cli.ExitConflict))
+	})
+
+	It("should show the status of a connector and its tasks", func() {
+		Expect(server.FailTask("logging", 1, "boom")).To(Succeed())
+		r := run("connectors", "status", "logging", "-a", server.URL)
+		Expect(r.code).To(Equal(cli.ExitOK))
+		Expect(r.stdout).To(MatchRegexp(`(?m)^NAME\s+TYPE\s+ID\s+STATE\s+WORKER$`))
+		Expect(r.stdout).To(MatchRegexp(`(?m)^logging\s+connector\s+RUNNING`))
+		Expect(r.stdout).To(MatchRegexp(`(?m)^logging\s+task\s+1\s+FAILED`))
+
+		r = run("connectors", "status", "logging", "-a", server.URL, "-o", "json")
+		var status kafkaconnect.Status
+		Expect(json.Unmarshal([]byte(r.stdout), &status)).To(Succeed())
+		Expect(status.GetFailedTasks()).To(Equal([]int{1}))
+	})
+
+	It("should show the status of every connector with a single request", func() {
+		server.AddConnector(kafkaconnect.Connector{Name: "metrics", Config: map[string]string{"tasks.max": "1"}})
+		Expect(server.FailConnector("metrics", "java.lang.OutOfMemoryError")).To(Succeed())
+		r := run("status", "-a", server.URL)
+		Expect(r.code).To(Equal(cli.ExitOK))
+		Expect(r.stdout).To(MatchRegexp(`(?m)^logging\s+task\s+1\s+RUNNING`))
+		Expect(r.stdout).To(MatchRegexp(`(?m)^metrics\s+connector\s+FAILED`))
+		Expect(server.Requests()).To(Equal([]string{"GET /connectors"}))
+
+		r = run("status", "missing", "-a", server.URL)
+		Expect(r.code).To(Equal(cli.ExitNotFound))
+	})
+
+	It("should watch until every connector is running", func() {
+		Expect(server.FailTask("logging", 0, "boom")).To(Succeed())
+		go func() {
+			defer GinkgoRecover()
+			time.Sleep(50 * time.Millisecond)
+			Expect(server.SetTaskState("logging", 0, kafkaconnecttest.StateRunning, "")).To(Succeed())
+		}()
+
+		r := run("status", "logging", "--until", "running", "--interval", "10ms", "--timeout", "5s", "-a", server.URL)
+		Expect(r.code).To(Equal(cli.ExitOK))
+		Expect(r.stdout).To(MatchRegexp(`(?m)^logging\s+RUNNING\s+\S+\s+1/2\s+task 0: boom$`))
+		Expect(r.stdout).To(MatchRegexp(`(?m)^\d\d:\d\d:\d\d logging RUNNING 1/2 -> RUNNING 2/2$`))
+	})
+
+	It("should watch a connector until it is created and fails", func() {
+		go func() {
+			defer GinkgoRecover()
+			time.Sleep(30 * time.Millisecond)
+			server.AddConnector(kafkaconnect.Connector{Name: "metrics", Config: map[string]string{"tasks.max": "1"}})
+			time.Sleep(30 * time.Millisecond)
+			Expect(server.FailTask("metrics", 0, "boom")).To(Succeed())
+		}()
+
+		r := run("status", "metrics", "--until", "failed", "--interval", "10ms", "-a", server.URL)
+		Expect(r.code).To(Equal(cli.ExitOK))
+		Expect(r.stdout).To(MatchRegexp(`(?m)^metrics\s+NOT FOUND`))
+		Expect(r.stdout).To(MatchRegexp(`(?m)metrics NOT FOUND -> RUNNING 1/1$`))
+		Expect(r.stdout).To(MatchRegexp(`(?m)metrics RUNNING 1/1 -> RUNNING 0/1 \(task 0: boom\)$`))
+	})
+
+	It("should fail when the --until condition times out", func() {
+		Expect(server.FailTask("logging", 0, "boom")).To(Succeed())
+		r := run("status", "--until", "running", "--interval", "10ms", "--timeout", "50ms", "-a", server.URL)
+		Expect(r.code).To(Equal(cli.ExitTimeout))
+		Expect(r.stderr).To(ContainSubstring("Timed out after 50ms waiting for the connectors to be running"))
+	})
+
+	It("should reject invalid watch options", func() {
+		Expect(run("status", "--until", "paused", "-a", server.URL).code).To(Equal(cli.ExitUsage))
+		Expect(run("status", "--watch", "-o", "json", "-a", server.URL).code).To(Equal(cli.ExitUsage))
+		Expect(run("status", "a", "b", "-a", server.URL).code).To(Equal(cli.ExitUsage))
+	})
+
+	It("should apply every connector of a manifest", func() {
+		r := run("connectors", "apply", "-f", "testdata/connectors.yaml", "--set", "tasks=3", "-a", server.URL)
+		Expect(r.code).To(Equal(cli.ExitOK))
+		Expect(r.stdout).To(Equal("logging configured\nmetrics configured\n"))
+		Expect(server.Connectors()).To(ConsistOf("logging", "metrics"))
+		logging, _ := server.Connector("logging")
+		Expect(logging.Config["tasks.max"]).To(Equal("3"))
+		Expect(logging.Config["topics"]).To(Equal("dumblogger-logs,_ims.logs"))
+	})
+
+	It("should validate connectors before applying them", func() {
+		r := run("connectors", "apply", "-f", "testdata/connectors.yaml", "--set", "tasks=0", "--validate", "local", "-a", server.URL)
+		Expect(r.code).To(Equal(cli.ExitError))
+		Expect(r.stderr).To(ContainSubstring("Invalid value for 'tasks.max'"))
+		logging, _ := server.Connector("logging")
+		Expect(logging.Config["tasks.max"]).To(Equal("2"))
+
+		Expect(run("connectors", "apply", "-f", "testdata/connectors.yaml", "--validate", "strict", "-a", server.URL).code).To(Equal(cli.ExitUsage))
+	})
+
+	It("should report connectors that could not be created", func() {
+		r := run("connectors", "create", "-f", "testdata/connectors.yaml", "-a", server.URL, "-o", "name")
+		Expect(r.code).To(Equal(cli.ExitConflict))
+		Expect(r.stdout).To(Equal("metrics\n"))
+		Expect(r.stderr).To(ContainSubstring("Error: logging: "))
+		Expect(r.stderr).To(ContainSubstring("1 of 2 connector(s) could not be created"))
+		Expect(r.stderr).NotTo(ContainSubstring("s3cr3t"))
+	})
+
+	It("should migrate connectors to another cluster", func() {
+		target := kafkaconnecttest.NewServer()
+		defer target.Close()
+		journal := filepath.Join(configDir, "logging.journal")
+		defer os.Remove(journal)
+		Expect(server.SetOffsets("logging", []kafkaconnecttest.Offset{{
+			Partition: map[string]interface{}{"kafka_topic": "dumblogger-logs", "kafka_partition": float64(0)},
+			Offset:    map[string]interface{}{"kafka_offset": float64(1024)},
+		}})).To(Succeed())
+
+		r := run("connectors", "migrate", "logging", "-a", server.URL, "--to", target.URL, "--stop", "--dry-run")
+		Expect(r.code).To(Equal(cli.ExitOK))
+		Expect(r.stdout).To(MatchRegexp(`(?m)^create-target\s+planned\s+create the connector on the target cluster, STOPPED$`))
+		Expect(target.Connectors()).To(BeEmpty())
+
+		r = run("connectors", "migrate", "logging", "-a", server.URL, "--to", target.URL,
+			"--source-action", "pause", "--journal", journal, "--interval", "10ms", "-o", "json")
+		Expect(r.code).To(Equal(cli.ExitOK))
+		var result map[string]interface{}
+		Expect(json.Unmarshal([]byte(r.stdout), &result)).To(Succeed())
+		Expect(result["steps"]).To(HaveLen(7))
+		Expect(r.stdout).NotTo(ContainSubstring("s3cr3t"))
+
+		migrated, ok := target.Connector("logging")
+		Expect(ok).To(BeTrue())
+		Expect(migrated.Config["connection.password"]).To(Equal("s3cr3t"))
+		offsets, _ := target.Offsets("logging")
+		Expect(offsets).To(HaveLen(1))
+		status, _ := server.Status("logging")
+		Expect(status.Connector.State).To(Equal(kafkaconnect.StatePaused))
+
+		r = run("connectors", "migrate", "logging", "-a", server.URL, "--to", target.URL, "--journal", journal)
+		Expect(r.code).To(Equal(cli.ExitOK))
+		Expect(r.stdout).To(MatchRegexp(`(?m)^retire-source\s+resumed\s*$`))
+
+		Expect(run("connectors", "migrate", "logging", "-a", server.URL).code).To(Equal(cli.ExitUsage))
+		Expect(run("connectors", "migrate", "logging", "-a", server.URL, "--to", target.URL, "--source-action", "keep").code).To(Equal(cli.ExitUsage))
+	})
+
+	It("should delete and restart connectors", func() {
+		r := run("connectors", "restart-task", "logging", "1", "-a", server.URL, "-o", "json")
+		Expect(r.code).To(Equal(cli.ExitOK))
+		Expect(r.stdout).To(MatchJSON(`{"name": "logging", "result": "success"}`))
+
+		Expect(run("connectors", "restart-task", "logging", "one", "-a", server.URL).code).To(Equal(cli.ExitUsage))
+
+		r = run("connectors", "delete", "logging", "-a", server.URL)
+		Expect(r.code).To(Equal(cli.ExitOK))
+		Expect(r.stdout).To(Equal("logging deleted\n"))
+		Expect(server.Connectors()).To(BeEmpty())
+	})
+
+	It("should render manifests without a Kafka Connect address", func() {
+		r := run("render", "-f", "testdata/connectors.yaml")
+		Expect(r.code).To(Equal(cli.ExitOK))
+		var connectors []kafkaconnect.Connector
+		Expect(json.Unmarshal([]byte(r.stdout), &connectors)).To(Succeed())
+		Expect(connectors).To(HaveLen(2))
+		Expect(connectors[0].Config["tasks.max"]).To(Equal("2"))
+		Expect(connectors[0].Config["connection.password"]).To(Equal(kafkaconnect.RedactedValue))
+
+		r = run("render", "-f", "testdata/connectors.yaml", "-o", "name")
+		Expect(r.stdout).To(Equal("logging\nmetrics\n"))
+	})
+
+	It("should only render sensitive values unredacted when asked to", func() {
+		for _, flag := range []string{"--no-redact", "--redact="} {
+			r := run("render", "-f", "testdata/connectors.yaml", flag)
+			Expect(r.code).To(Equal(cli.ExitOK))
+			var connectors []kafkaconnect.Connector
+			Expect(json.Unmarshal([]byte(r.stdout), &connectors)).To(Succeed())
+			Expect(connectors[0].Config["connection.password"]).To(Equal("s3cr3t"))
+		}
+
+		r := run("render", "-f", "testdata/connectors.yaml", "--redact", "*.class")
+		var connectors []kafkaconnect.Connector
+		Expect(json.Unmarshal([]byte(r.stdout), &connectors)).To(Succeed())
+		Expect(connectors[0].Config["connection.password"]).To(Equal("s3cr3t"))
+		Expect(connectors[0].Config["connector.class"]).To(Equal(kafkaconnect.RedactedValue))
+	})
+})
+
+var _ = Describe("kaya config", func() {
+	var (
+		server     *kafkaconnecttest.Server
+		configPath string
+	)
+
+	BeforeEach(func() {
+		server = kafkaconnecttest.NewServer()
+		server.AddConnector(kafkaconnect.Connector{Name: "logging", Config: map[string]string{"tasks.max": "1"}})
+
+		configPath = filepath.Join(configDir, "contexts")
+		config := &cli.Config{
+			CurrentContext: "us-east",
+			Contexts: []cli.Context{
+				{Name: "us-east", URLs: []string{"127.0.0.1:1"}},
+				{
+					Name: "us-west",
+					// The first worker is down, requests fail over to the second one.
+					URLs:  []string{"http://127.0.0.1:1", server.URL},
+					Retry: &cli.RetryConfig{Count: 0},
+					Auth:  &cli.AuthConfig{Type: "basic", Username: "kaya", Password: "${env:KAYA_TEST_PASSWORD}"},
+				},
+			},
+		}
+		os.Setenv("KAYA_TEST_PASSWORD", "secret")
+		Expect(config.Save(configPath)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		server.Close()
+		os.Remove(configPath)
+	})
+
+	It("should list contexts", func() {
+		r := run("config", "get-contexts", "--kayaconfig", configPath)
+		Expect(r.code).To(Equal(cli.ExitOK))
+		Expect(r.stdout).To(MatchRegexp(`(?m)^\*\s+us-east\s+127.0.0.1:1$`))
+		Expect(r.stdout).To(MatchRegexp(`(?m)^\s+us-west\s+http://127.0.0.1:1,http`))
+
+		r = run("config", "current-context", "--kayaconfig", configPath)
+		Expect(r.stdout).To(Equal("us-east\n"))
+	})
+
+	It("should switch the current context", func() {
+		r := run("config", "use-context", "us-west", "--kayaconfig", configPath)
+		Expect(r.code).To(Equal(cli.ExitOK))
+		Expect(r.stdout).To(Equal("Switched to context \"us-west\".\n"))
+
+		config, err := cli.LoadConfig(configPath)
+		Expect(err).To(BeNil())
+		Expect(config.CurrentContext).To(Equal("us-west"))
+		Expect(config.Contexts).To(HaveLen(2))
+
+		info, err := os.Stat(configPath)
+		Expect(err).To(BeNil())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+
+		r = run("connectors", "list", "--kayaconfig", configPath)
+		Expect(r.code).To(Equal(cli.ExitOK))
+		Expect(r.stdout).To(Equal("NAME\nlogging\n"))
+	})
+
+	It("should use the context given with --context", func() {
+		r := run("connectors", "get", "logging", "--context", "us-west", "--kayaconfig", configPath, "-o", "name")
+		Expect(r.code).To(Equal(cli.ExitOK))
+		Expect(r.stdout).To(Equal("logging\n"))
+	})
+
+	It("should let --addr override the context URLs", func() {
+		r := run("connectors", "list", "--context", "us-east", "--addr", server.URL, "--kayaconfig", configPath)
+		Expect(r.code).To(Equal(cli.ExitOK))
+	})
+
+	It("should reject unknown contexts", func() {
+		r := run("config", "use-context", "eu-central", "--kayaconfig", configPath)
+		Expect(r.code).To(Equal(cli.ExitError))
+		Expect(r.stderr).To(ContainSubstring(`Context "eu-central" not found`))
+
+		r = run("connectors", "list", "--context", "eu-central", "--kayaconfig", configPath)
+		Expect(r.code).To(Equal(cli.ExitUsage))
+	})
+})
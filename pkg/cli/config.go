@@ -0,0 +1,231 @@
+package cli
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/walmartdigital/go-kaya/pkg/kafkaconnect"
+	"gopkg.in/yaml.v2"
+)
+
+// ConfigEnv overrides the location of the configuration file.
+const ConfigEnv = "KAYA_CONFIG"
+
+// Default retry policy, used when neither the context nor the flags set one.
+const (
+	defaultRetryCount   = 3
+	defaultRetryWait    = 1 * time.Second
+	defaultRetryMaxWait = 30 * time.Second
+)
+
+// Config is the content of the kaya configuration file, ~/.kaya/config by default. Like a
+// kubeconfig it holds named contexts, one per Kafka Connect cluster:
+//
+//	current-context: us-east
+//	contexts:
+//	  - name: us-east
+//	    urls: [https://connect-0.us-east:8083, https://connect-1.us-east:8083]
+//	    tls:
+//	      ca-file: /etc/kaya/us-east-ca.pem
+//	    auth:
+//	      type: basic
+//	      username: kaya
+//	      password: ${env:KAYA_US_EAST_PASSWORD}
+//	    retry:
+//	      count: 5
+//	      wait: 2s
+//	      max-wait: 1m
+//	    timeout: 30s
+//
+// Passwords and tokens may reference config providers, as in connector configs.
+type Config struct {
+	CurrentContext string    `yaml:"current-context,omitempty"`
+	Contexts       []Context `yaml:"contexts"`
+}
+
+// Context holds the settings used to reach one Kafka Connect cluster.
+type Context struct {
+	Name string `yaml:"name"`
+	// URLs are workers of the same cluster, tried in order. See client.FailoverClient.
+	URLs    []string      `yaml:"urls"`
+	TLS     *TLSConfig    `yaml:"tls,omitempty"`
+	Auth    *AuthConfig   `yaml:"auth,omitempty"`
+	Retry   *RetryConfig  `yaml:"retry,omitempty"`
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+}
+
+// TLSConfig ...
+type TLSConfig struct {
+	CAFile             string `yaml:"ca-file,omitempty"`
+	CertFile           string `yaml:"cert-file,omitempty"`
+	KeyFile            string `yaml:"key-file,omitempty"`
+	ServerName         string `yaml:"server-name,omitempty"`
+	InsecureSkipVerify bool   `yaml:"insecure-skip-verify,omitempty"`
+}
+
+// AuthConfig ...
+type AuthConfig struct {
+	// Type is one of none, basic or token.
+	Type     string `yaml:"type"`
+	Username string `yaml:"username,omitempty"`
+	Password string `yaml:"password,omitempty"`
+	Token    string `yaml:"token,omitempty"`
+}
+
+// RetryConfig ...
+type RetryConfig struct {
+	Count   int           `yaml:"count"`
+	Wait    time.Duration `yaml:"wait,omitempty"`
+	MaxWait time.Duration `yaml:"max-wait,omitempty"`
+}
+
+// DefaultConfigPath returns $KAYA_CONFIG, or ~/.kaya/config.
+func DefaultConfigPath() string {
+	if path := os.Getenv(ConfigEnv); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".kaya", "config")
+	}
+	return filepath.Join(home, ".kaya", "config")
+}
+
+// LoadConfig reads the configuration file at path. A missing file is an empty
+// configuration.
+func LoadConfig(path string) (*Config, error) {
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var config Config
+	if err := yaml.UnmarshalStrict(content, &config); err != nil {
+		return nil, fmt.Errorf("Invalid configuration file %s: %s", path, err.Error())
+	}
+	return &config, nil
+}
+
+// Save writes the configuration to path, readable by the current user only as it may
+// hold credentials.
+func (c *Config) Save(path string) error {
+	content, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, content, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Context returns the context called name.
+func (c *Config) Context(name string) (*Context, error) {
+	for i := range c.Contexts {
+		if c.Contexts[i].Name == name {
+			return &c.Contexts[i], nil
+		}
+	}
+	return nil, fmt.Errorf("Context %q not found", name)
+}
+
+// Options returns the client options of the context. The first URL is returned apart as
+// it is the base URL of the client.
+func (ctx Context) Options() (string, []kafkaconnect.Option, error) {
+	if len(ctx.URLs) == 0 {
+		return "", nil, fmt.Errorf("Context %q has no URL", ctx.Name)
+	}
+
+	retry := RetryConfig{Count: defaultRetryCount, Wait: defaultRetryWait, MaxWait: defaultRetryMaxWait}
+	if ctx.Retry != nil {
+		retry = *ctx.Retry
+	}
+	opts := []kafkaconnect.Option{
+		kafkaconnect.WithRetryPolicy(retry.Count, retry.Wait, retry.MaxWait, nil),
+	}
+	if len(ctx.URLs) > 1 {
+		opts = append(opts, kafkaconnect.WithFailoverURLs(ctx.URLs[1:]...))
+	}
+	if ctx.Timeout > 0 {
+		opts = append(opts, kafkaconnect.WithTimeout(ctx.Timeout))
+	}
+
+	if ctx.TLS != nil {
+		tlsConfig, err := ctx.TLS.build()
+		if err != nil {
+			return "", nil, fmt.Errorf("Invalid TLS settings in context %q: %s", ctx.Name, err.Error())
+		}
+		opts = append(opts, kafkaconnect.WithTLSConfig(tlsConfig))
+	}
+
+	if ctx.Auth != nil {
+		opt, err := ctx.Auth.option()
+		if err != nil {
+			return "", nil, fmt.Errorf("Invalid auth settings in context %q: %s", ctx.Name, err.Error())
+		}
+		if opt != nil {
+			opts = append(opts, opt)
+		}
+	}
+	return ctx.URLs[0], opts, nil
+}
+
+func (t TLSConfig) build() (*tls.Config, error) {
+	config := &tls.Config{
+		ServerName:         t.ServerName,
+		InsecureSkipVerify: t.InsecureSkipVerify,
+	}
+	if t.CAFile != "" {
+		pem, err := ioutil.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("No certificate found in %s", t.CAFile)
+		}
+		config.RootCAs = pool
+	}
+	if t.CertFile != "" || t.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
+
+func (a AuthConfig) option() (kafkaconnect.Option, error) {
+	resolver := kafkaconnect.NewSecretResolver()
+	switch a.Type {
+	case "", "none":
+		return nil, nil
+	case "basic":
+		password, err := resolver.ResolveValue("password", a.Password)
+		if err != nil {
+			return nil, err
+		}
+		return kafkaconnect.WithBasicAuth(a.Username, password), nil
+	case "token":
+		token, err := resolver.ResolveValue("token", a.Token)
+		if err != nil {
+			return nil, err
+		}
+		return kafkaconnect.WithTokenAuth(token), nil
+	default:
+		return nil, errors.New("Unknown auth type " + a.Type + ", expected none, basic or token")
+	}
+}
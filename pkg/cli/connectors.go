@@ -0,0 +1,234 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/walmartdigital/go-kaya/pkg/kafkaconnect"
+)
+
+var connectorCommands = []command{
+	{
+		path:  []string{"connectors", "list"},
+		short: "List the connectors deployed on Kafka Connect",
+		flags: clientFlags,
+		run:   listConnectors,
+	},
+	{
+		path:  []string{"connectors", "get"},
+		args:  "NAME",
+		nargs: 1,
+		short: "Show the configuration of a connector",
+		flags: clientFlags,
+		run:   getConnector,
+	},
+	{
+		path:  []string{"connectors", "status"},
+		args:  "[NAME]",
+		nargs: -1,
+		short: "Show the state of one or all connectors and their tasks",
+		flags: statusFlags,
+		run:   connectorStatus,
+	},
+	{
+		path:  []string{"status"},
+		args:  "[NAME]",
+		nargs: -1,
+		short: "Alias of connectors status",
+		flags: statusFlags,
+		run:   connectorStatus,
+	},
+	{
+		path:  []string{"connectors", "create"},
+		short: "Create the connectors of the given manifests",
+		flags: clientAndManifestFlags,
+		run:   changeConnectors("created", kafkaconnect.ConnectorManager.Create),
+	},
+	{
+		path:  []string{"connectors", "update"},
+		short: "Update existing connectors from the given manifests",
+		flags: clientAndManifestFlags,
+		run:   changeConnectors("updated", kafkaconnect.ConnectorManager.Update),
+	},
+	{
+		path:  []string{"connectors", "apply"},
+		short: "Create or update the connectors of the given manifests",
+		flags: clientAndManifestFlags,
+		run:   changeConnectors("configured", kafkaconnect.ConnectorManager.Apply),
+	},
+	{
+		path:  []string{"connectors", "delete"},
+		args:  "NAME",
+		nargs: 1,
+		short: "Delete a connector",
+		flags: clientFlags,
+		run: connectorAction("deleted", func(c kafkaconnect.ConnectorManager, args []string) (*kafkaconnect.Response, error) {
+			return c.Delete(args[0])
+		}),
+	},
+	{
+		path:  []string{"connectors", "restart"},
+		args:  "NAME",
+		nargs: 1,
+		short: "Restart a connector",
+		flags: clientFlags,
+		run: connectorAction("restarted", func(c kafkaconnect.ConnectorManager, args []string) (*kafkaconnect.Response, error) {
+			return c.RestartConnector(args[0])
+		}),
+	},
+	{
+		path:  []string{"connectors", "restart-task"},
+		args:  "NAME TASK",
+		nargs: 2,
+		short: "Restart a task of a connector",
+		flags: clientFlags,
+		run: connectorAction("task restarted", func(c kafkaconnect.ConnectorManager, args []string) (*kafkaconnect.Response, error) {
+			taskID, err := strconv.Atoi(args[1])
+			if err != nil {
+				return nil, usageError("Invalid task ID %q", args[1])
+			}
+			return c.RestartTask(args[0], taskID)
+		}),
+	},
+	{
+		path:  []string{"render"},
+		short: "Print the connectors of the given manifests after templating and overlays, redacted unless --no-redact is given",
+		flags: manifestFlags,
+		run:   renderConnectors,
+	},
+}
+
+func listConnectors(e *env, args []string) error {
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	resp, err := c.List()
+	if err != nil {
+		return responseError(resp, err)
+	}
+
+	names, _ := resp.Payload.([]string)
+	sort.Strings(names)
+	r := result{value: names, names: names, header: []string{"NAME"}}
+	for _, n := range names {
+		r.rows = append(r.rows, []string{n})
+	}
+	if r.value == nil {
+		r.value = []string{}
+	}
+	return e.printer.print(r)
+}
+
+func getConnector(e *env, args []string) error {
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	resp, err := c.Read(args[0])
+	if err != nil {
+		return responseError(resp, err)
+	}
+
+	config, _ := resp.Payload.(map[string]string)
+	return e.printer.print(e.connectorResult(kafkaconnect.Connector{Name: args[0], Config: config}))
+}
+
+// connectorResult prints a connector as its manifest in the json and yaml formats and as
+// its config in the table format. Sensitive values are always redacted.
+func (e *env) connectorResult(connector kafkaconnect.Connector) result {
+	redacted := e.redactor.RedactConnector(connector)
+	r := result{value: redacted, names: []string{connector.Name}, header: []string{"KEY", "VALUE"}}
+
+	keys := make([]string, 0, len(redacted.Config))
+	for k := range redacted.Config {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		r.rows = append(r.rows, []string{k, redacted.Config[k]})
+	}
+	return r
+}
+
+// changeConnectors runs a client method taking a connector for every connector of the
+// manifests. Every connector is attempted; the first failure determines the exit code.
+func changeConnectors(verb string, change func(kafkaconnect.ConnectorManager, kafkaconnect.Connector) (*kafkaconnect.Response, error)) func(*env, []string) error {
+	return func(e *env, args []string) error {
+		connectors, err := e.connectors()
+		if err != nil {
+			return err
+		}
+		c, err := e.client()
+		if err != nil {
+			return err
+		}
+
+		var results []result
+		code := ExitOK
+		for _, connector := range connectors {
+			resp, err := change(c, connector)
+			if err != nil {
+				fmt.Fprintf(e.stderr, "Error: %s: %s\n", connector.Name, err.Error())
+				if code == ExitOK {
+					code = exitCode(responseError(resp, err))
+				}
+				continue
+			}
+
+			applied := connector
+			if payload, ok := resp.Payload.(kafkaconnect.Connector); ok {
+				applied = payload
+			}
+			r := e.connectorResult(applied)
+			r.header = nil
+			r.rows = [][]string{{applied.Name + " " + verb}}
+			results = append(results, r)
+		}
+
+		if len(results) > 0 {
+			if err := e.printer.print(merge(results)); err != nil {
+				return err
+			}
+		}
+		if code != ExitOK {
+			return &exitError{code: code, err: fmt.Errorf("%d of %d connector(s) could not be %s", len(connectors)-len(results), len(connectors), verb)}
+		}
+		return nil
+	}
+}
+
+// connectorAction runs a client method that returns no payload.
+func connectorAction(verb string, action func(kafkaconnect.ConnectorManager, []string) (*kafkaconnect.Response, error)) func(*env, []string) error {
+	return func(e *env, args []string) error {
+		c, err := e.client()
+		if err != nil {
+			return err
+		}
+		resp, err := action(c, args)
+		if err != nil {
+			if exitCode(err) == ExitUsage {
+				return err
+			}
+			return responseError(resp, err)
+		}
+		return e.printer.print(result{
+			value: map[string]string{"name": args[0], "result": resp.Result},
+			names: []string{args[0]},
+			rows:  [][]string{{args[0] + " " + verb}},
+		})
+	}
+}
+
+func renderConnectors(e *env, args []string) error {
+	connectors, err := e.connectors()
+	if err != nil {
+		return err
+	}
+	results := make([]result, 0, len(connectors))
+	for _, c := range connectors {
+		results = append(results, e.connectorResult(c))
+	}
+	return e.printer.withDefault(FormatJSON).print(merge(results))
+}
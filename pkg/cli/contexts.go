@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+var configCommands = []command{
+	{
+		path:  []string{"config", "get-contexts"},
+		short: "List the contexts of the configuration file",
+		run:   getContexts,
+	},
+	{
+		path:  []string{"config", "current-context"},
+		short: "Print the current context",
+		run:   currentContext,
+	},
+	{
+		path:  []string{"config", "use-context"},
+		args:  "NAME",
+		nargs: 1,
+		short: "Set the current context",
+		run:   useContext,
+	},
+}
+
+func getContexts(e *env, args []string) error {
+	config, err := LoadConfig(e.opts.configPath)
+	if err != nil {
+		return err
+	}
+
+	type contextInfo struct {
+		Name    string   `json:"name"`
+		URLs    []string `json:"urls"`
+		Current bool     `json:"current"`
+	}
+	infos := make([]contextInfo, 0, len(config.Contexts))
+	r := result{header: []string{"CURRENT", "NAME", "URL"}}
+	for _, ctx := range config.Contexts {
+		current := ctx.Name == config.CurrentContext
+		infos = append(infos, contextInfo{Name: ctx.Name, URLs: ctx.URLs, Current: current})
+		marker := ""
+		if current {
+			marker = "*"
+		}
+		r.names = append(r.names, ctx.Name)
+		r.rows = append(r.rows, []string{marker, ctx.Name, strings.Join(ctx.URLs, ",")})
+	}
+	r.value = infos
+	return e.printer.print(r)
+}
+
+func currentContext(e *env, args []string) error {
+	config, err := LoadConfig(e.opts.configPath)
+	if err != nil {
+		return err
+	}
+	if config.CurrentContext == "" {
+		return fmt.Errorf("Current context is not set")
+	}
+	_, err = fmt.Fprintln(e.stdout, config.CurrentContext)
+	return err
+}
+
+func useContext(e *env, args []string) error {
+	config, err := LoadConfig(e.opts.configPath)
+	if err != nil {
+		return err
+	}
+	if _, err := config.Context(args[0]); err != nil {
+		return err
+	}
+	config.CurrentContext = args[0]
+	if err := config.Save(e.opts.configPath); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(e.stdout, "Switched to context %q.\n", args[0])
+	return err
+}
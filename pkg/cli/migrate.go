@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"time"
+
+	flag "github.com/spf13/pflag"
+	"github.com/walmartdigital/go-kaya/pkg/kafkaconnect"
+	"github.com/walmartdigital/go-kaya/pkg/migrate"
+)
+
+var migrateCommands = []command{
+	{
+		path:  []string{"connectors", "migrate"},
+		args:  "NAME",
+		nargs: 1,
+		short: "Move a connector to another Kafka Connect cluster",
+		flags: migrateFlags,
+		run:   migrateConnector,
+	},
+}
+
+func migrateFlags(fs *flag.FlagSet, o *options) {
+	// Secrets are never resolved: the config read from the source holds the references of
+	// its workers' config providers, which the target workers are expected to resolve.
+	fs.StringVarP(&o.addr, "addr", "a", "", "Kafka Connect address of the source cluster, overriding the context")
+	fs.StringVar(&o.to, "to", "", "Kafka Connect address of the target cluster, overriding --to-context")
+	fs.StringVar(&o.toContext, "to-context", "", "Context of the target cluster")
+	fs.StringVar(&o.validate, "validate", string(kafkaconnect.ValidationOff), "Validate the connector config before creating it on the target: off, local, remote or both")
+	fs.BoolVar(&o.stop, "stop", false, "Stop the connector on the source before reading its offsets")
+	fs.BoolVar(&o.offsets, "offsets", true, "Copy the offsets of the connector when the source supports it, which requires Kafka Connect 3.7 on the target")
+	fs.StringVar(&o.sourceAction, "source-action", string(migrate.DeleteSource), "What to do with the connector on the source once it runs on the target: delete or pause")
+	fs.BoolVar(&o.dryRun, "dry-run", false, "Only read both clusters and print the planned steps")
+	fs.StringVar(&o.journal, "journal", "", "File recording the completed steps, to continue a failed migration by running it again")
+	fs.DurationVar(&o.timeout, "timeout", time.Minute, "How long to wait for the connector to reach the state expected after a step")
+	fs.DurationVar(&o.interval, "interval", time.Second, "Polling interval of the connector status")
+}
+
+// targetClient creates the client of the cluster given with --to or --to-context.
+func (e *env) targetClient() (*kafkaconnect.Client, error) {
+	if e.opts.to == "" && e.opts.toContext == "" {
+		return nil, usageError("A target cluster is required, use --to or --to-context")
+	}
+	var ctx *Context
+	if e.opts.toContext != "" {
+		config, err := LoadConfig(e.opts.configPath)
+		if err != nil {
+			return nil, err
+		}
+		if ctx, err = config.Context(e.opts.toContext); err != nil {
+			return nil, usageError("%s", err)
+		}
+	}
+	return e.newClient(e.opts.to, ctx)
+}
+
+func migrateConnector(e *env, args []string) error {
+	action, err := migrate.ParseSourceAction(e.opts.sourceAction)
+	if err != nil {
+		return usageError("%s", err)
+	}
+	source, err := e.client()
+	if err != nil {
+		return err
+	}
+	target, err := e.targetClient()
+	if err != nil {
+		return err
+	}
+
+	opts := []migrate.Option{
+		migrate.WithSourceAction(action),
+		migrate.WithWait(e.opts.timeout, e.opts.interval),
+	}
+	if e.opts.stop {
+		opts = append(opts, migrate.WithStop())
+	}
+	if !e.opts.offsets {
+		opts = append(opts, migrate.WithoutOffsets())
+	}
+	if e.opts.dryRun {
+		opts = append(opts, migrate.WithDryRun())
+	}
+	if e.opts.journal != "" {
+		opts = append(opts, migrate.WithJournal(e.opts.journal))
+	}
+
+	// The steps that ran are printed also when the migration fails, to tell how far it went.
+	res, err := migrate.New(source, target, opts...).Migrate(args[0])
+	r := result{value: res, names: []string{args[0]}, header: []string{"STEP", "STATUS", "MESSAGE"}}
+	for _, step := range res.Steps {
+		r.rows = append(r.rows, []string{step.Name, step.Status, step.Message})
+	}
+	if printErr := e.printer.print(r); printErr != nil {
+		return printErr
+	}
+	return err
+}
@@ -0,0 +1,215 @@
+package cli
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"github.com/go-logr/zapr"
+	flag "github.com/spf13/pflag"
+	"github.com/walmartdigital/go-kaya/pkg/kafkaconnect"
+	"github.com/walmartdigital/go-kaya/pkg/render"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// options holds the flags of every command. Each command only registers the ones it uses.
+type options struct {
+	addr       string
+	context    string
+	configPath string
+	output     string
+	verbose    bool
+	redact     []string
+	noRedact   bool
+
+	resolveSecrets bool
+	passthrough    []string
+	validate       string
+
+	watch    bool
+	interval time.Duration
+	until    string
+	timeout  time.Duration
+
+	to           string
+	toContext    string
+	stop         bool
+	offsets      bool
+	sourceAction string
+	dryRun       bool
+	journal      string
+
+	files       []string
+	overlays    []string
+	valuesFiles []string
+	assignments []string
+}
+
+func (o *options) addGlobalFlags(fs *flag.FlagSet) {
+	fs.StringVarP(&o.output, "output", "o", "", "Output format, one of table, json, yaml or name")
+	fs.BoolVarP(&o.verbose, "verbose", "v", false, "Log requests to Kafka Connect on stderr")
+	fs.StringVar(&o.context, "context", "", "Context of the configuration file to use instead of the current one")
+	fs.StringVar(&o.configPath, "kayaconfig", DefaultConfigPath(), "Path to the configuration file, also set with $"+ConfigEnv)
+	fs.StringSliceVar(&o.redact, "redact", kafkaconnect.DefaultRedactPatterns, "Config key patterns whose values are hidden from the output, none when empty")
+	fs.BoolVar(&o.noRedact, "no-redact", false, "Show sensitive config values, e.g. to apply the output of render")
+}
+
+func clientFlags(fs *flag.FlagSet, o *options) {
+	fs.StringVarP(&o.addr, "addr", "a", "", "Kafka Connect address in the form of <host:port> or a URL, overriding the context")
+	fs.BoolVar(&o.resolveSecrets, "resolve-secrets", true, "Resolve ${provider:[path:]key} references in the connector config before sending it")
+	fs.StringSliceVar(&o.passthrough, "passthrough-providers", []string{}, "Config providers whose references are sent unchanged to Kafka Connect")
+}
+
+func manifestFlags(fs *flag.FlagSet, o *options) {
+	fs.StringSliceVarP(&o.files, "file", "f", []string{}, "Connector manifest file or directory, JSON or YAML")
+	fs.StringSliceVar(&o.valuesFiles, "values", []string{}, "Values files used to render the manifests, later files taking precedence")
+	fs.StringSliceVar(&o.overlays, "overlay", []string{}, "Manifests merged in order on top of the manifests given with --file")
+	fs.StringArrayVar(&o.assignments, "set", []string{}, "Set a template value as path=value, overriding values files")
+}
+
+func clientAndManifestFlags(fs *flag.FlagSet, o *options) {
+	clientFlags(fs, o)
+	manifestFlags(fs, o)
+	fs.StringVar(&o.validate, "validate", string(kafkaconnect.ValidationOff), "Validate connector configs before sending them: off, local, remote or both")
+}
+
+// env is what a command runs with.
+type env struct {
+	opts     *options
+	stdout   io.Writer
+	stderr   io.Writer
+	redactor *kafkaconnect.Redactor
+	printer  *printer
+}
+
+func newEnv(o *options, stdout io.Writer, stderr io.Writer) (*env, error) {
+	e := &env{
+		opts:     o,
+		stdout:   stdout,
+		stderr:   stderr,
+		redactor: kafkaconnect.NewRedactor(o.redact...),
+	}
+	// NewRedactor falls back to the default patterns, an explicit --redact= hides nothing.
+	if o.noRedact || len(o.redact) == 0 {
+		e.redactor = &kafkaconnect.Redactor{}
+	}
+	p, err := newPrinter(o.output, stdout)
+	if err != nil {
+		return nil, err
+	}
+	e.printer = p
+	return e, nil
+}
+
+// client creates the Kafka Connect client. The settings come from the context given with
+// --context, or the current context when --addr is not given. --addr overrides the URLs
+// of the context.
+func (e *env) client() (*kafkaconnect.Client, error) {
+	ctx, err := e.context()
+	if err != nil {
+		return nil, err
+	}
+	return e.newClient(e.opts.addr, ctx)
+}
+
+// newClient creates a Kafka Connect client from the settings of ctx, with its URLs
+// replaced by addr when addr is given, or for addr alone when ctx is nil.
+func (e *env) newClient(addr string, ctx *Context) (*kafkaconnect.Client, error) {
+	var err error
+	opts := []kafkaconnect.Option{
+		kafkaconnect.WithRetryPolicy(defaultRetryCount, defaultRetryWait, defaultRetryMaxWait, nil),
+	}
+
+	if ctx != nil {
+		if addr != "" {
+			// --addr replaces every URL of the context, which must not be tried as failovers.
+			ctx.URLs = []string{addr}
+		}
+		addr, opts, err = ctx.Options()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if addr == "" {
+		return nil, usageError("A Kafka Connect address is required, use --addr or set a context with 'kaya config use-context'")
+	}
+
+	opts = append(opts, kafkaconnect.WithRedactor(e.redactor))
+	if e.opts.validate != "" {
+		mode, err := kafkaconnect.ParseValidationMode(e.opts.validate)
+		if err != nil {
+			return nil, usageError("%s", err)
+		}
+		opts = append(opts, kafkaconnect.WithValidation(mode))
+	}
+	if e.opts.verbose {
+		opts = append(opts, kafkaconnect.WithLogger(zapr.NewLogger(e.logger())))
+	}
+	if e.opts.resolveSecrets {
+		resolver := kafkaconnect.NewSecretResolver()
+		resolver.Passthrough(e.opts.passthrough...)
+		opts = append(opts, kafkaconnect.WithSecretResolver(resolver))
+	}
+	return kafkaconnect.New(addr, opts...)
+}
+
+// context returns the context selected with --context, or the current context unless
+// --addr is given. It returns nil when no context applies.
+func (e *env) context() (*Context, error) {
+	name := e.opts.context
+	if name == "" && e.opts.addr != "" {
+		return nil, nil
+	}
+	config, err := LoadConfig(e.opts.configPath)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		name = config.CurrentContext
+	}
+	if name == "" {
+		return nil, nil
+	}
+	ctx, err := config.Context(name)
+	if err != nil {
+		return nil, usageError("%s", err)
+	}
+	return ctx, nil
+}
+
+// logger writes human readable diagnostics to stderr.
+func (e *env) logger() *zap.Logger {
+	encoder := zapcore.NewConsoleEncoder(zap.NewDevelopmentEncoderConfig())
+	return zap.New(zapcore.NewCore(encoder, zapcore.AddSync(e.stderr), zapcore.DebugLevel))
+}
+
+// connectors renders the manifests given with --file.
+func (e *env) connectors() ([]kafkaconnect.Connector, error) {
+	if len(e.opts.files) == 0 {
+		return nil, usageError("A connector manifest is required, use --file")
+	}
+	values, err := render.LoadValues(e.opts.valuesFiles...)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range e.opts.assignments {
+		if err := values.Set(a); err != nil {
+			return nil, usageError("%s", err)
+		}
+	}
+
+	var connectors []kafkaconnect.Connector
+	for _, file := range e.opts.files {
+		t := render.Template{Base: file, Overlays: e.opts.overlays, Values: values}
+		c, err := t.RenderAll()
+		if err != nil {
+			return nil, err
+		}
+		connectors = append(connectors, c...)
+	}
+	if len(connectors) == 0 {
+		return nil, errors.New("No connector found in the given manifests")
+	}
+	return connectors, nil
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Output formats accepted by -o.
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+	FormatYAML  = "yaml"
+	FormatName  = "name"
+)
+
+// result is the output of a command, in every format.
+type result struct {
+	// value is printed by the json and yaml formats.
+	value interface{}
+	// names are printed by the name format, one per line.
+	names []string
+	// header and rows are printed by the table format. Rows without a header are printed
+	// as plain lines.
+	header []string
+	rows   [][]string
+}
+
+type printer struct {
+	format string
+	out    io.Writer
+}
+
+func newPrinter(format string, out io.Writer) (*printer, error) {
+	switch format {
+	case "", FormatTable, FormatJSON, FormatYAML, FormatName:
+		return &printer{format: format, out: out}, nil
+	default:
+		return nil, usageError("Unknown output format %q, expected one of table, json, yaml or name", format)
+	}
+}
+
+// withDefault returns a printer using format unless -o was given.
+func (p *printer) withDefault(format string) *printer {
+	if p.format != "" {
+		return p
+	}
+	return &printer{format: format, out: p.out}
+}
+
+func (p *printer) print(r result) error {
+	switch p.format {
+	case FormatJSON:
+		b, err := json.MarshalIndent(r.value, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(p.out, string(b))
+		return err
+	case FormatYAML:
+		b, err := toYAML(r.value)
+		if err != nil {
+			return err
+		}
+		_, err = p.out.Write(b)
+		return err
+	case FormatName:
+		for _, n := range r.names {
+			fmt.Fprintln(p.out, n)
+		}
+		return nil
+	default:
+		w := tabwriter.NewWriter(p.out, 0, 4, 2, ' ', 0)
+		if r.header != nil {
+			fmt.Fprintln(w, strings.Join(r.header, "\t"))
+		}
+		for _, row := range r.rows {
+			fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+		return w.Flush()
+	}
+}
+
+// toYAML goes through JSON so the json tags of the client types are honoured.
+func toYAML(value interface{}) ([]byte, error) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	if err := yaml.Unmarshal(b, &generic); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(generic)
+}
+
+// merge combines the results of several operations into one, printing a list in the json
+// and yaml formats when there is more than one.
+func merge(results []result) result {
+	if len(results) == 1 {
+		return results[0]
+	}
+	merged := result{}
+	values := make([]interface{}, 0, len(results))
+	for _, r := range results {
+		values = append(values, r.value)
+		merged.names = append(merged.names, r.names...)
+		merged.rows = append(merged.rows, r.rows...)
+		if merged.header == nil {
+			merged.header = r.header
+		}
+	}
+	merged.value = values
+	return merged
+}
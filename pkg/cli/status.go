@@ -0,0 +1,320 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	flag "github.com/spf13/pflag"
+	"github.com/walmartdigital/go-kaya/pkg/kafkaconnect"
+)
+
+// Conditions accepted by status --until.
+const (
+	// UntilRunning waits for every connector and all of its tasks to be RUNNING.
+	UntilRunning = "running"
+	// UntilFailed waits for a connector or one of its tasks to be FAILED.
+	UntilFailed = "failed"
+)
+
+// maxTraceLength is the width of the TRACE column of the status table.
+const maxTraceLength = 60
+
+const (
+	ansiReset       = "\x1b[0m"
+	ansiRed         = "\x1b[31m"
+	ansiYellow      = "\x1b[33m"
+	ansiClearScreen = "\x1b[H\x1b[2J"
+)
+
+func statusFlags(fs *flag.FlagSet, o *options) {
+	clientFlags(fs, o)
+	fs.BoolVarP(&o.watch, "watch", "w", false, "Keep polling and refresh the status until interrupted")
+	fs.DurationVar(&o.interval, "interval", 2*time.Second, "Polling interval of --watch")
+	fs.StringVar(&o.until, "until", "", "Watch until every connector is running, or until one is failed")
+	fs.DurationVar(&o.timeout, "timeout", 5*time.Minute, "Fail if the --until condition is not met within this duration")
+}
+
+// connectorState is the status of a watched connector, which may not exist (yet).
+type connectorState struct {
+	name    string
+	status  kafkaconnect.Status
+	missing bool
+}
+
+func (s connectorState) state() string {
+	if s.missing {
+		return "NOT FOUND"
+	}
+	return s.status.Connector.State
+}
+
+func (s connectorState) tasks() string {
+	if s.missing {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d", s.status.GetActiveTasksCount(), s.status.GetTaskCount())
+}
+
+// summary identifies the observable state of a connector, to detect transitions.
+func (s connectorState) summary() string {
+	return strings.TrimSpace(s.state() + " " + s.tasks())
+}
+
+func (s connectorState) failed() bool {
+	return !s.missing && (s.status.IsConnectorFailed() || len(s.status.GetFailedTasks()) > 0)
+}
+
+func (s connectorState) running() bool {
+	return !s.missing && s.status.Connector.State == "RUNNING" &&
+		s.status.GetTaskCount() > 0 && s.status.GetActiveTasksCount() == s.status.GetTaskCount()
+}
+
+// trace returns the first line of the connector failure, or of the first failed task.
+func (s connectorState) trace() string {
+	trace := s.status.Connector.Trace
+	if trace == "" {
+		for _, t := range s.status.Tasks {
+			if t.State == "FAILED" {
+				trace = fmt.Sprintf("task %d: %s", t.ID, t.Trace)
+				break
+			}
+		}
+	}
+	trace = strings.TrimSpace(strings.SplitN(trace, "\n", 2)[0])
+	if len(trace) > maxTraceLength {
+		trace = trace[:maxTraceLength-3] + "..."
+	}
+	return trace
+}
+
+func (s connectorState) row() []string {
+	worker := ""
+	if !s.missing {
+		worker = s.status.Connector.WorkerID
+	}
+	return []string{s.name, s.state(), worker, s.tasks(), s.trace()}
+}
+
+// statusHeader is the header of the summary table redrawn by --watch.
+var statusHeader = []string{"NAME", "STATE", "WORKER", "TASKS", "TRACE"}
+
+// taskRows lists the connector and each of its tasks on a row of their own.
+func (s connectorState) taskRows() [][]string {
+	rows := [][]string{{s.name, "connector", "", s.status.Connector.State, s.status.Connector.WorkerID}}
+	for _, t := range s.status.Tasks {
+		rows = append(rows, []string{s.name, "task", strconv.Itoa(t.ID), t.State, t.WorkerID})
+	}
+	return rows
+}
+
+func connectorStatus(e *env, args []string) error {
+	if len(args) > 1 {
+		return usageError("At most one connector name is accepted, got %d", len(args))
+	}
+	until := e.opts.until
+	if until != "" && until != UntilRunning && until != UntilFailed {
+		return usageError("Unknown --until condition %q, expected %s or %s", until, UntilRunning, UntilFailed)
+	}
+	watch := e.opts.watch || until != ""
+	if watch && e.printer.format != "" && e.printer.format != FormatTable {
+		return usageError("--watch only supports the table output")
+	}
+
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	if watch {
+		return e.watchStatus(c, args, until)
+	}
+
+	states, err := pollStatus(c, args)
+	if err != nil {
+		return err
+	}
+	if len(args) == 1 && states[0].missing {
+		return &exitError{code: ExitNotFound, err: fmt.Errorf("Connector %s not found", args[0])}
+	}
+
+	r := result{header: []string{"NAME", "TYPE", "ID", "STATE", "WORKER"}}
+	statuses := make([]kafkaconnect.Status, 0, len(states))
+	for _, s := range states {
+		statuses = append(statuses, s.status)
+		r.names = append(r.names, s.name)
+		r.rows = append(r.rows, s.taskRows()...)
+	}
+	r.value = statuses
+	if len(args) == 1 {
+		r.value = statuses[0]
+	}
+	return e.printer.print(r)
+}
+
+// pollStatus gets the status of the named connectors, or of every connector when names is
+// empty, with a single request.
+func pollStatus(c kafkaconnect.ConnectorManager, names []string) ([]connectorState, error) {
+	resp, err := c.ListStatus()
+	if err != nil {
+		return nil, responseError(resp, err)
+	}
+	statuses, _ := resp.Payload.(map[string]kafkaconnect.Status)
+
+	if len(names) == 0 {
+		for name := range statuses {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	states := make([]connectorState, 0, len(names))
+	for _, name := range names {
+		status, ok := statuses[name]
+		states = append(states, connectorState{name: name, status: status, missing: !ok})
+	}
+	return states, nil
+}
+
+func conditionMet(states []connectorState, until string) bool {
+	switch until {
+	case UntilRunning:
+		for _, s := range states {
+			if !s.running() {
+				return false
+			}
+		}
+		return len(states) > 0
+	case UntilFailed:
+		for _, s := range states {
+			if s.failed() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// watchStatus polls the status until the until condition is met, the timeout elapses or
+// the command is interrupted, which fails while an until condition is pending. On a terminal the table is redrawn on every poll, with
+// changed rows in yellow and failed ones in red. Otherwise the table is printed once,
+// followed by a line per transition, which suits CI logs.
+func (e *env) watchStatus(c kafkaconnect.ConnectorManager, names []string, until string) error {
+	tty := isTerminal(e.stdout)
+
+	var deadline <-chan time.Time
+	if until != "" && e.opts.timeout > 0 {
+		timer := time.NewTimer(e.opts.timeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	ticker := time.NewTicker(e.opts.interval)
+	defer ticker.Stop()
+
+	var previous map[string]string
+	for {
+		states, err := pollStatus(c, names)
+		if err != nil {
+			// Errors are expected during rollouts, e.g. conflicts while the cluster rebalances.
+			fmt.Fprintf(e.stderr, "Error: %s\n", err.Error())
+		} else {
+			current := make(map[string]string, len(states))
+			for _, s := range states {
+				current[s.name] = s.summary()
+			}
+			switch {
+			case tty:
+				e.redrawStatus(states, previous)
+			case previous == nil:
+				if err := e.printer.print(result{header: statusHeader, rows: rowsOf(states)}); err != nil {
+					return err
+				}
+			default:
+				e.printTransitions(states, previous)
+			}
+			previous = current
+
+			if conditionMet(states, until) {
+				return nil
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-deadline:
+			return &exitError{code: ExitTimeout, err: fmt.Errorf("Timed out after %s waiting for the connectors to be %s", e.opts.timeout, until)}
+		case <-interrupt:
+			if until != "" {
+				return &exitError{code: ExitError, err: fmt.Errorf("Interrupted before the connectors were %s", until)}
+			}
+			return nil
+		}
+	}
+}
+
+func rowsOf(states []connectorState) [][]string {
+	rows := make([][]string, 0, len(states))
+	for _, s := range states {
+		rows = append(rows, s.row())
+	}
+	return rows
+}
+
+func (e *env) printTransitions(states []connectorState, previous map[string]string) {
+	now := time.Now().Format("15:04:05")
+	for _, s := range states {
+		before, ok := previous[s.name]
+		if !ok {
+			fmt.Fprintf(e.stdout, "%s %s %s\n", now, s.name, s.summary())
+			continue
+		}
+		if before != s.summary() {
+			line := fmt.Sprintf("%s %s %s -> %s", now, s.name, before, s.summary())
+			if trace := s.trace(); trace != "" {
+				line += " (" + trace + ")"
+			}
+			fmt.Fprintln(e.stdout, line)
+		}
+	}
+}
+
+func (e *env) redrawStatus(states []connectorState, previous map[string]string) {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(statusHeader, "\t"))
+	for _, s := range states {
+		fmt.Fprintln(w, strings.Join(s.row(), "\t"))
+	}
+	w.Flush()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	fmt.Fprint(e.stdout, ansiClearScreen)
+	fmt.Fprintf(e.stdout, "Every %s, updated %s\n\n%s\n", e.opts.interval, time.Now().Format("15:04:05"), lines[0])
+	for i, s := range states {
+		line := lines[i+1]
+		switch {
+		case s.failed():
+			line = ansiRed + line + ansiReset
+		case previous != nil && previous[s.name] != s.summary():
+			line = ansiYellow + line + ansiReset
+		}
+		fmt.Fprintln(e.stdout, line)
+	}
+}
+
+func isTerminal(w interface{}) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,140 @@
+package cassette_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/walmartdigital/go-kaya/pkg/client"
+	"github.com/walmartdigital/go-kaya/pkg/client/cassette"
+	"github.com/walmartdigital/go-kaya/pkg/kafkaconnect"
+	"github.com/walmartdigital/go-kaya/pkg/kafkaconnect/kafkaconnecttest"
+)
+
+func TestAll(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Cassette")
+}
+
+const goldenFile = "testdata/logging_lifecycle.json"
+
+var loggingConnector = kafkaconnect.Connector{
+	Name: "logging",
+	Config: map[string]string{
+		"connector.class": "io.confluent.connect.elasticsearch.ElasticsearchSinkConnector",
+		"type.name":       "log",
+		"topics":          "_dumblogger.logs",
+		"topic.index.map": "_dumblogger.logs:<logs-pd-dumblogger-{now/d}>",
+		"batch.size":      "100",
+		"connection.url":  "http://elasticsearch-master.default.svc.cluster.local:9200",
+		"key.ignore":      "true",
+		"schema.ignore":   "true",
+	},
+}
+
+// loggingLifecycle is the scenario stored in the golden file. Set KAYA_RECORD_URL to the
+// address of a Kafka Connect cluster, e.g. the one in e2e/, to record it again.
+func loggingLifecycle(kcc *kafkaconnect.Client) {
+	resp, err := kcc.Create(loggingConnector)
+	Expect(err).To(BeNil())
+	Expect(resp.Result).To(Equal("success"))
+
+	resp, err = kcc.Read("logging")
+	Expect(err).To(BeNil())
+	Expect(resp.Payload.(map[string]string)).To(HaveKeyWithValue("topics", "_dumblogger.logs"))
+
+	resp, err = kcc.GetStatus("logging")
+	Expect(err).To(BeNil())
+	Expect(resp.Payload.(kafkaconnect.Status).Name).To(Equal("logging"))
+
+	resp, err = kcc.Delete("logging")
+	Expect(err).To(BeNil())
+	Expect(resp.Result).To(Equal("success"))
+
+	resp, err = kcc.Read("logging")
+	Expect(err).NotTo(BeNil())
+	Expect(resp.Result).To(Equal("notfound"))
+}
+
+var _ = Describe("Cassettes", func() {
+	It("should record interactions and replay them", func() {
+		server := kafkaconnecttest.NewServer()
+		defer server.Close()
+
+		recorder := &cassette.RecordingFactory{Factory: client.SimpleHTTPClientFactory{}}
+		kcc, err := kafkaconnect.New(server.URL, kafkaconnect.WithHTTPClientFactory(recorder))
+		Expect(err).To(BeNil())
+		loggingLifecycle(kcc)
+
+		dir, err := ioutil.TempDir("", "cassette")
+		Expect(err).To(BeNil())
+		defer os.RemoveAll(dir)
+
+		path := filepath.Join(dir, "cassette.json")
+		Expect(recorder.Save(path)).To(Succeed())
+
+		c, err := cassette.Load(path)
+		Expect(err).To(BeNil())
+		Expect(c.Interactions()).To(HaveLen(5))
+		Expect(c.Interactions()[0].Method).To(Equal("POST"))
+		Expect(c.Interactions()[0].StatusCode).To(Equal(201))
+
+		replayed, err := kafkaconnect.New("somehost", kafkaconnect.WithHTTPClientFactory(cassette.ReplayFactory{Cassette: c}))
+		Expect(err).To(BeNil())
+		loggingLifecycle(replayed)
+	})
+
+	It("should replay the golden file", func() {
+		if url := os.Getenv("KAYA_RECORD_URL"); url != "" {
+			recorder := &cassette.RecordingFactory{Factory: client.SimpleHTTPClientFactory{}}
+			kcc, err := kafkaconnect.New(url, kafkaconnect.WithHTTPClientFactory(recorder))
+			Expect(err).To(BeNil())
+			loggingLifecycle(kcc)
+			Expect(recorder.Save(goldenFile)).To(Succeed())
+		}
+
+		c, err := cassette.Load(goldenFile)
+		Expect(err).To(BeNil())
+
+		player := cassette.NewPlayer(c, false)
+		kcc, err := kafkaconnect.NewClient("somehost", client.HTTPClientConfig{}, playerFactory{player})
+		Expect(err).To(BeNil())
+		loggingLifecycle(kcc)
+		Expect(player.Remaining()).To(BeEmpty())
+	})
+
+	It("should fail requests that were not recorded", func() {
+		player := cassette.NewPlayer(cassette.New(), false)
+		status, _, err := player.Get("/connectors")
+		Expect(err).NotTo(BeNil())
+		Expect(status).To(Equal(0))
+	})
+
+	It("should match request bodies regardless of key order", func() {
+		player := cassette.NewPlayer(cassette.New(cassette.Interaction{
+			Method:       "PUT",
+			Endpoint:     "/connectors/logging/config",
+			RequestBody:  `{"a":"1","b":"2"}`,
+			StatusCode:   200,
+			ResponseBody: `{}`,
+		}), false)
+
+		status, _, err := player.Put("/connectors/logging/config", []byte(`{ "b": "2", "a": "1" }`))
+		Expect(err).To(BeNil())
+		Expect(status).To(Equal(200))
+
+		_, _, err = player.Put("/connectors/logging/config", []byte(`{"a":"1","b":"2"}`))
+		Expect(err).NotTo(BeNil())
+	})
+})
+
+type playerFactory struct {
+	player *cassette.Player
+}
+
+func (f playerFactory) Create(url string, config client.HTTPClientConfig) (client.HTTPClient, error) {
+	return f.player, nil
+}
@@ -0,0 +1,119 @@
+package cassette
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/walmartdigital/go-kaya/pkg/client"
+)
+
+// ReplayFactory is a client.HTTPClientFactory whose clients serve interactions from a
+// cassette instead of reaching Kafka Connect. The URL and configuration given to Create
+// are ignored.
+type ReplayFactory struct {
+	Cassette *Cassette
+	// Repeat allows an interaction to be served more than once. By default every recorded
+	// interaction is served at most once, in recording order.
+	Repeat bool
+}
+
+// Create ...
+func (f ReplayFactory) Create(url string, config client.HTTPClientConfig) (client.HTTPClient, error) {
+	if f.Cassette == nil {
+		return nil, errors.New("No cassette to replay")
+	}
+	return NewPlayer(f.Cassette, f.Repeat), nil
+}
+
+// Player is a client.HTTPClient that serves recorded interactions. Requests are matched on
+// method, endpoint and body; among the matching interactions the earliest one that has
+// not been served yet is returned.
+type Player struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	served       []bool
+	repeat       bool
+}
+
+// NewPlayer returns a Player serving the interactions of cassette.
+func NewPlayer(cassette *Cassette, repeat bool) *Player {
+	interactions := cassette.Interactions()
+	return &Player{
+		interactions: interactions,
+		served:       make([]bool, len(interactions)),
+		repeat:       repeat,
+	}
+}
+
+// Get ...
+func (p *Player) Get(endpoint string) (int, *[]byte, error) {
+	return p.replay(http.MethodGet, endpoint, nil)
+}
+
+// Post ...
+func (p *Player) Post(endpoint string, body []byte) (int, *[]byte, error) {
+	return p.replay(http.MethodPost, endpoint, body)
+}
+
+// Put ...
+func (p *Player) Put(endpoint string, body []byte) (int, *[]byte, error) {
+	return p.replay(http.MethodPut, endpoint, body)
+}
+
+// Delete ...
+func (p *Player) Delete(endpoint string) (int, *[]byte, error) {
+	return p.replay(http.MethodDelete, endpoint, nil)
+}
+
+// Patch ...
+func (p *Player) Patch(endpoint string, body []byte) (int, *[]byte, error) {
+	return p.replay(http.MethodPatch, endpoint, body)
+}
+
+// Remaining returns the interactions that have not been served yet.
+func (p *Player) Remaining() []Interaction {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var remaining []Interaction
+	for i, interaction := range p.interactions {
+		if !p.served[i] {
+			remaining = append(remaining, interaction)
+		}
+	}
+	return remaining
+}
+
+func (p *Player) replay(method string, endpoint string, body []byte) (int, *[]byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	match := -1
+	for i, interaction := range p.interactions {
+		if interaction.Method != method || interaction.Endpoint != endpoint || !sameBody(interaction.RequestBody, string(body)) {
+			continue
+		}
+		if !p.served[i] {
+			match = i
+			break
+		}
+		if p.repeat && match < 0 {
+			match = i
+		}
+	}
+
+	if match < 0 {
+		return 0, &[]byte{}, fmt.Errorf("No recorded interaction for %s %s", method, endpoint)
+	}
+
+	p.served[match] = true
+	interaction := p.interactions[match]
+	respBody := []byte(interaction.ResponseBody)
+
+	var err error
+	if interaction.Error != "" {
+		err = errors.New(interaction.Error)
+	}
+	return interaction.StatusCode, &respBody, err
+}
@@ -0,0 +1,98 @@
+package cassette
+
+import (
+	"net/http"
+
+	"github.com/walmartdigital/go-kaya/pkg/client"
+)
+
+// Recorder is a client.HTTPClient decorator that records every call made through it.
+type Recorder struct {
+	next     client.HTTPClient
+	cassette *Cassette
+}
+
+// NewRecorder records every call made through next into cassette.
+func NewRecorder(next client.HTTPClient, cassette *Cassette) *Recorder {
+	return &Recorder{next: next, cassette: cassette}
+}
+
+// Get ...
+func (r *Recorder) Get(endpoint string) (int, *[]byte, error) {
+	status, body, err := r.next.Get(endpoint)
+	r.record(http.MethodGet, endpoint, nil, status, body, err)
+	return status, body, err
+}
+
+// Post ...
+func (r *Recorder) Post(endpoint string, body []byte) (int, *[]byte, error) {
+	status, respBody, err := r.next.Post(endpoint, body)
+	r.record(http.MethodPost, endpoint, body, status, respBody, err)
+	return status, respBody, err
+}
+
+// Put ...
+func (r *Recorder) Put(endpoint string, body []byte) (int, *[]byte, error) {
+	status, respBody, err := r.next.Put(endpoint, body)
+	r.record(http.MethodPut, endpoint, body, status, respBody, err)
+	return status, respBody, err
+}
+
+// Delete ...
+func (r *Recorder) Delete(endpoint string) (int, *[]byte, error) {
+	status, body, err := r.next.Delete(endpoint)
+	r.record(http.MethodDelete, endpoint, nil, status, body, err)
+	return status, body, err
+}
+
+// Patch ...
+func (r *Recorder) Patch(endpoint string, body []byte) (int, *[]byte, error) {
+	status, respBody, err := client.Patch(r.next, endpoint, body)
+	r.record(http.MethodPatch, endpoint, body, status, respBody, err)
+	return status, respBody, err
+}
+
+func (r *Recorder) record(method string, endpoint string, reqBody []byte, status int, respBody *[]byte, err error) {
+	i := Interaction{
+		Method:      method,
+		Endpoint:    endpoint,
+		RequestBody: string(reqBody),
+		StatusCode:  status,
+	}
+	if respBody != nil {
+		i.ResponseBody = string(*respBody)
+	}
+	if err != nil {
+		i.Error = err.Error()
+	}
+	r.cassette.Add(i)
+}
+
+// RecordingFactory is a client.HTTPClientFactory whose clients record every call into a
+// single cassette.
+type RecordingFactory struct {
+	// Factory creates the clients that actually reach Kafka Connect.
+	Factory client.HTTPClientFactory
+	// Cassette receives the interactions. A new cassette is created on first use if nil.
+	Cassette *Cassette
+}
+
+// Create ...
+func (f *RecordingFactory) Create(url string, config client.HTTPClientConfig) (client.HTTPClient, error) {
+	hc, err := f.Factory.Create(url, config)
+	if err != nil {
+		return nil, err
+	}
+	if f.Cassette == nil {
+		f.Cassette = New()
+	}
+	return NewRecorder(hc, f.Cassette), nil
+}
+
+// Save writes the recorded interactions to a golden file.
+func (f *RecordingFactory) Save(path string) error {
+	if f.Cassette == nil {
+		f.Cassette = New()
+	}
+	return f.Cassette.Save(path)
+}
@@ -0,0 +1,373 @@
+package client_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	resty "github.com/go-resty/resty/v2"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/walmartdigital/go-kaya/pkg/client"
+	"github.com/walmartdigital/go-kaya/pkg/client/restyclient"
+)
+
+var factories = map[string]client.HTTPClientFactory{
+	"net/http": client.SimpleHTTPClientFactory{},
+	"resty":    restyclient.RestyClientFactory{},
+}
+
+func TestAll(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "HTTPClient")
+}
+
+var _ = Describe("Middleware chain", func() {
+	for name, factory := range factories {
+		name, factory := name, factory
+		Context("with the "+name+" client", func() {
+			middlewareSpecs(factory)
+		})
+	}
+})
+
+var _ = Describe("Transport configuration", func() {
+	for name, factory := range factories {
+		name, factory := name, factory
+		Context("with the "+name+" client", func() {
+			transportSpecs(factory)
+		})
+	}
+})
+
+func middlewareSpecs(factory client.HTTPClientFactory) {
+	var (
+		server   *httptest.Server
+		requests []*http.Request
+		bodies   []string
+		statuses []int
+	)
+
+	BeforeEach(func() {
+		requests = nil
+		bodies = nil
+		statuses = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			requests = append(requests, r)
+			bodies = append(bodies, string(body))
+			status := 200
+			if len(statuses) > 0 {
+				status, statuses = statuses[0], statuses[1:]
+			}
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(`{"name":"logging"}`))
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should apply headers and basic auth to every request", func() {
+		hc, err := factory.Create(server.URL, client.HTTPClientConfig{
+			Headers:  map[string]string{"Content-Type": "application/json"},
+			AuthType: client.BasicAuth,
+			Creds:    map[string]string{"username": "kaya", "password": "secret"},
+		})
+		Expect(err).To(BeNil())
+
+		status, body, err := hc.Post("/connectors", []byte(`{"name":"logging"}`))
+		Expect(err).To(BeNil())
+		Expect(status).To(Equal(200))
+		Expect(string(*body)).To(Equal(`{"name":"logging"}`))
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].Method).To(Equal("POST"))
+		Expect(requests[0].URL.Path).To(Equal("/connectors"))
+		Expect(requests[0].Header.Get("Content-Type")).To(Equal("application/json"))
+		user, pass, ok := requests[0].BasicAuth()
+		Expect(ok).To(BeTrue())
+		Expect(user).To(Equal("kaya"))
+		Expect(pass).To(Equal("secret"))
+		Expect(bodies[0]).To(Equal(`{"name":"logging"}`))
+	})
+
+	It("should run user middlewares before the built-in ones", func() {
+		var order []string
+		record := func(name string) client.Middleware {
+			return func(next client.RoundTripFunc) client.RoundTripFunc {
+				return func(req *client.Request) (*client.Response, error) {
+					order = append(order, name)
+					req.Header.Set("X-Request-Id", name)
+					return next(req)
+				}
+			}
+		}
+
+		hc, err := factory.Create(server.URL, client.HTTPClientConfig{
+			AuthType:    client.TokenAuth,
+			Creds:       map[string]string{"token": "abc"},
+			Middlewares: []client.Middleware{record("first"), record("second")},
+		})
+		Expect(err).To(BeNil())
+
+		_, _, err = hc.Get("/connectors")
+		Expect(err).To(BeNil())
+		Expect(order).To(Equal([]string{"first", "second"}))
+		Expect(requests[0].Header.Get("X-Request-Id")).To(Equal("second"))
+		Expect(requests[0].Header.Get("Authorization")).To(Equal("Bearer abc"))
+	})
+
+	It("should retry when a retry condition matches", func() {
+		statuses = []int{409, 409, 200}
+
+		hc, err := factory.Create(server.URL, client.HTTPClientConfig{
+			RetryCount: 3,
+			RetryCondition: func(resp *client.Response, err error) bool {
+				return resp != nil && resp.StatusCode == 409
+			},
+		})
+		Expect(err).To(BeNil())
+
+		status, _, err := hc.Put("/connectors/logging/config", []byte(`{}`))
+		Expect(err).To(BeNil())
+		Expect(status).To(Equal(200))
+		Expect(requests).To(HaveLen(3))
+		Expect(bodies).To(Equal([]string{`{}`, `{}`, `{}`}))
+	})
+
+	It("should send PATCH requests", func() {
+		hc, err := factory.Create(server.URL, client.HTTPClientConfig{})
+		Expect(err).To(BeNil())
+
+		status, _, err := client.Patch(hc, "/connectors/logging/offsets", []byte(`{"offsets":[]}`))
+		Expect(err).To(BeNil())
+		Expect(status).To(Equal(200))
+		Expect(requests[0].Method).To(Equal(http.MethodPatch))
+		Expect(bodies).To(Equal([]string{`{"offsets":[]}`}))
+	})
+
+	It("should honor a go-resty retry condition adapted by restyclient", func() {
+		statuses = []int{500, 200}
+
+		hc, err := factory.Create(server.URL, client.HTTPClientConfig{
+			RetryCount: 1,
+			RetryCondition: restyclient.RetryCondition(func(resp *resty.Response, err error) bool {
+				return resp.StatusCode() == 500
+			}),
+		})
+		Expect(err).To(BeNil())
+
+		status, _, err := hc.Put("/connectors/logging/config", []byte(`{}`))
+		Expect(err).To(BeNil())
+		Expect(status).To(Equal(200))
+		Expect(requests).To(HaveLen(2))
+	})
+
+	It("should bound all the attempts of a call with the timeout", func() {
+		statuses = []int{500, 500, 500, 500, 500, 500, 500, 500, 500, 500}
+
+		hc, err := factory.Create(server.URL, client.HTTPClientConfig{
+			Timeout:       100 * time.Millisecond,
+			RetryCount:    10,
+			RetryWaitTime: 40 * time.Millisecond,
+			RetryCondition: func(resp *client.Response, err error) bool {
+				return resp.StatusCode >= 500
+			},
+		})
+		Expect(err).To(BeNil())
+
+		start := time.Now()
+		status, _, _ := hc.Get("/connectors")
+		Expect(time.Since(start)).To(BeNumerically("<", 300*time.Millisecond))
+		Expect(status).To(Equal(500))
+		Expect(len(requests)).To(BeNumerically("<", 5))
+	})
+
+	It("should report every attempt to the metrics recorder", func() {
+		statuses = []int{500, 204}
+		recorder := &fakeRecorder{}
+
+		hc, err := factory.Create(server.URL, client.HTTPClientConfig{
+			RetryCount: 1,
+			Metrics:    recorder,
+			RetryCondition: func(resp *client.Response, err error) bool {
+				return resp.StatusCode >= 500
+			},
+		})
+		Expect(err).To(BeNil())
+
+		status, _, err := hc.Delete("/connectors/logging")
+		Expect(err).To(BeNil())
+		Expect(status).To(Equal(204))
+		Expect(recorder.statuses).To(Equal([]int{500, 204}))
+	})
+}
+
+func transportSpecs(factory client.HTTPClientFactory) {
+	It("should fail a request exceeding the configured timeout", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(200 * time.Millisecond)
+			w.WriteHeader(200)
+		}))
+		defer server.Close()
+
+		hc, err := factory.Create(server.URL, client.HTTPClientConfig{
+			Timeout: 50 * time.Millisecond,
+		})
+		Expect(err).To(BeNil())
+
+		_, _, err = hc.Get("/connectors")
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should fail an attempt exceeding the attempt timeout", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(200 * time.Millisecond)
+			w.WriteHeader(200)
+		}))
+		defer server.Close()
+
+		hc, err := factory.Create(server.URL, client.HTTPClientConfig{
+			AttemptTimeout: 50 * time.Millisecond,
+		})
+		Expect(err).To(BeNil())
+
+		_, _, err = hc.Get("/connectors")
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should only retry errors of requests that are safe to repeat", func() {
+		hits := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits++
+			conn, _, err := w.(http.Hijacker).Hijack()
+			Expect(err).To(BeNil())
+			conn.Close()
+		}))
+		defer server.Close()
+
+		config := client.HTTPClientConfig{RetryCount: 2, RetryWaitTime: time.Millisecond}
+		hc, err := factory.Create(server.URL, config)
+		Expect(err).To(BeNil())
+
+		_, _, err = hc.Post("/connectors", []byte(`{}`))
+		Expect(err).NotTo(BeNil())
+		Expect(hits).To(Equal(1))
+
+		_, _, err = hc.Get("/connectors")
+		Expect(err).NotTo(BeNil())
+		Expect(hits).To(Equal(4))
+
+		recorder := &fakeRecorder{}
+		config.Metrics = recorder
+		hc, err = factory.Create("http://127.0.0.1:1", config)
+		Expect(err).To(BeNil())
+		_, _, err = hc.Post("/connectors", []byte(`{}`))
+		Expect(err).NotTo(BeNil())
+		Expect(recorder.statuses).To(HaveLen(3))
+	})
+
+	It("should route requests through the configured proxy", func() {
+		var proxied string
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			proxied = r.URL.String()
+			w.WriteHeader(204)
+		}))
+		defer proxy.Close()
+
+		hc, err := factory.Create("http://kafka-connect.invalid:8083", client.HTTPClientConfig{
+			ProxyURL:     proxy.URL,
+			MaxIdleConns: 10,
+			DialTimeout:  time.Second,
+		})
+		Expect(err).To(BeNil())
+
+		status, _, err := hc.Delete("/connectors/logging")
+		Expect(err).To(BeNil())
+		Expect(status).To(Equal(204))
+		Expect(proxied).To(Equal("http://kafka-connect.invalid:8083/connectors/logging"))
+	})
+
+	It("should reject a malformed proxy URL", func() {
+		_, err := factory.Create("http://somehost", client.HTTPClientConfig{
+			ProxyURL: "://proxy",
+		})
+		Expect(err).NotTo(BeNil())
+	})
+}
+
+type fakeRecorder struct {
+	statuses []int
+}
+
+func (f *fakeRecorder) ObserveRequest(method string, endpoint string, status int, duration time.Duration, err error) {
+	f.statuses = append(f.statuses, status)
+}
+
+var _ = Describe("Failover", func() {
+	var (
+		server *httptest.Server
+		hits   int
+	)
+
+	BeforeEach(func() {
+		hits = 0
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits++
+			w.WriteHeader(http.StatusOK)
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	for name, factory := range factories {
+		factory := factory
+
+		It("should fail over to the next worker with "+name, func() {
+			hc, err := client.FailoverFactory{Factory: factory, URLs: []string{server.URL}}.Create("http://127.0.0.1:1", client.HTTPClientConfig{})
+			Expect(err).To(BeNil())
+
+			status, _, err := hc.Get("/connectors")
+			Expect(err).To(BeNil())
+			Expect(status).To(Equal(http.StatusOK))
+
+			status, _, err = hc.Post("/connectors", []byte("{}"))
+			Expect(err).To(BeNil())
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(hits).To(Equal(2))
+		})
+	}
+
+	It("should fail when no worker can be reached", func() {
+		_, _, err := client.NewFailoverClient().Get("/connectors")
+		Expect(err).NotTo(BeNil())
+
+		hc, err := client.FailoverFactory{URLs: []string{"http://127.0.0.1:2"}}.Create("http://127.0.0.1:1", client.HTTPClientConfig{})
+		Expect(err).To(BeNil())
+		_, _, err = hc.Get("/connectors")
+		Expect(err).NotTo(BeNil())
+	})
+})
+
+var _ = Describe("HTTPClientConfig matcher", func() {
+	It("should compare the timeout, transport and proxy settings", func() {
+		config := client.HTTPClientConfig{RetryCount: 3, Timeout: time.Second}
+		matcher := client.MatchHTTPClientConfig(config)
+		Expect(matcher.Matches(config)).To(BeTrue())
+
+		for _, other := range []client.HTTPClientConfig{
+			{RetryCount: 3, Timeout: 2 * time.Second},
+			{RetryCount: 3, Timeout: time.Second, AttemptTimeout: time.Second},
+			{RetryCount: 3, Timeout: time.Second, ProxyURL: "http://proxy:3128"},
+			{RetryCount: 3, Timeout: time.Second, MaxIdleConns: 10},
+			{RetryCount: 3, Timeout: time.Second, RetryCondition: func(*client.Response, error) bool { return true }},
+		} {
+			Expect(matcher.Matches(other)).To(BeFalse(), "%+v", other)
+		}
+	})
+})
@@ -0,0 +1,121 @@
+package client
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+)
+
+// FailoverClient is an HTTPClient spreading requests over several Kafka Connect workers of
+// the same cluster. Requests go to the last worker that answered and move on to the next
+// one when it cannot be reached. POST requests, which are not idempotent, only fail over
+// when the connection could not be established.
+type FailoverClient struct {
+	mu      sync.Mutex
+	clients []HTTPClient
+	current int
+}
+
+// NewFailoverClient returns a FailoverClient trying clients in order.
+func NewFailoverClient(clients ...HTTPClient) *FailoverClient {
+	return &FailoverClient{clients: clients}
+}
+
+// Get ...
+func (f *FailoverClient) Get(endpoint string) (int, *[]byte, error) {
+	return f.do(http.MethodGet, func(c HTTPClient) (int, *[]byte, error) {
+		return c.Get(endpoint)
+	})
+}
+
+// Post ...
+func (f *FailoverClient) Post(endpoint string, body []byte) (int, *[]byte, error) {
+	return f.do(http.MethodPost, func(c HTTPClient) (int, *[]byte, error) {
+		return c.Post(endpoint, body)
+	})
+}
+
+// Put ...
+func (f *FailoverClient) Put(endpoint string, body []byte) (int, *[]byte, error) {
+	return f.do(http.MethodPut, func(c HTTPClient) (int, *[]byte, error) {
+		return c.Put(endpoint, body)
+	})
+}
+
+// Delete ...
+func (f *FailoverClient) Delete(endpoint string) (int, *[]byte, error) {
+	return f.do(http.MethodDelete, func(c HTTPClient) (int, *[]byte, error) {
+		return c.Delete(endpoint)
+	})
+}
+
+// Patch fails over like Put, since altering offsets is idempotent.
+func (f *FailoverClient) Patch(endpoint string, body []byte) (int, *[]byte, error) {
+	return f.do(http.MethodPatch, func(c HTTPClient) (int, *[]byte, error) {
+		return Patch(c, endpoint, body)
+	})
+}
+
+func (f *FailoverClient) do(method string, call func(HTTPClient) (int, *[]byte, error)) (int, *[]byte, error) {
+	if len(f.clients) == 0 {
+		return 0, &[]byte{}, errors.New("No Kafka Connect worker configured")
+	}
+
+	f.mu.Lock()
+	start := f.current
+	f.mu.Unlock()
+
+	var status int
+	var body *[]byte
+	var err error
+	for i := 0; i < len(f.clients); i++ {
+		n := (start + i) % len(f.clients)
+		status, body, err = call(f.clients[n])
+		if err == nil || (method == http.MethodPost && !isDialError(err)) {
+			f.mu.Lock()
+			f.current = n
+			f.mu.Unlock()
+			return status, body, err
+		}
+	}
+	return status, body, err
+}
+
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// FailoverFactory is an HTTPClientFactory creating FailoverClients. The URL given to
+// Create is tried first, followed by URLs.
+type FailoverFactory struct {
+	// Factory creates the client of each worker. Defaults to SimpleHTTPClientFactory.
+	Factory HTTPClientFactory
+	URLs    []string
+}
+
+// Create ...
+func (f FailoverFactory) Create(url string, config HTTPClientConfig) (HTTPClient, error) {
+	factory := f.Factory
+	if factory == nil {
+		factory = SimpleHTTPClientFactory{}
+	}
+
+	urls := []string{url}
+	for _, u := range f.URLs {
+		if u != url {
+			urls = append(urls, u)
+		}
+	}
+
+	clients := make([]HTTPClient, 0, len(urls))
+	for _, u := range urls {
+		c, err := factory.Create(u, config)
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, c)
+	}
+	return NewFailoverClient(clients...), nil
+}
@@ -1,10 +1,12 @@
 package client
 
 import (
+	"crypto/tls"
+	"fmt"
 	"reflect"
 	"time"
 
-	resty "github.com/go-resty/resty/v2"
+	"github.com/go-logr/logr"
 	"github.com/golang/mock/gomock"
 )
 
@@ -18,13 +20,37 @@ const (
 
 // HTTPClientConfig ...
 type HTTPClientConfig struct {
-	Headers            map[string]string
-	Creds              map[string]string
-	AuthType           AuthType
-	RetryCount         int
-	RetryWaitTime      time.Duration
-	RetryWaitMaxTime   time.Duration
-	RetryConditionFunc resty.RetryConditionFunc
+	Headers          map[string]string
+	Creds            map[string]string
+	AuthType         AuthType
+	RetryCount       int
+	RetryWaitTime    time.Duration
+	RetryWaitMaxTime time.Duration
+	// RetryCondition retries a request whenever it returns true for the response of an
+	// attempt, on top of the retries of requests failing with an error, see RetryMiddleware.
+	RetryCondition RetryConditionFunc
+	// Timeout bounds a whole call, including its retries and the waits between them. Zero
+	// means no timeout.
+	Timeout time.Duration
+	// AttemptTimeout bounds a single HTTP attempt, zero means no timeout.
+	AttemptTimeout time.Duration
+	TLSConfig      *tls.Config
+	// Transport tuning, zero values keep the net/http defaults. A negative KeepAlive
+	// disables TCP keep-alives.
+	DialTimeout         time.Duration
+	TLSHandshakeTimeout time.Duration
+	KeepAlive           time.Duration
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+	// ProxyURL routes requests through an HTTP proxy. When empty the proxy is taken
+	// from the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+	ProxyURL string
+	// Middlewares are applied to every request before the built-in retry, metrics,
+	// logging, headers and auth middlewares, in that order.
+	Middlewares []Middleware
+	Metrics     MetricsRecorder
+	Logger      logr.Logger
 }
 
 // MatchHTTPClientConfig ...
@@ -32,7 +58,8 @@ func MatchHTTPClientConfig(h HTTPClientConfig) gomock.Matcher {
 	return &h
 }
 
-// Matches ...
+// Matches compares the settings of both configs. Functions, middlewares, metrics and
+// loggers cannot be compared, so they only have to be set in both configs or in neither.
 func (h *HTTPClientConfig) Matches(x interface{}) bool {
 	obj := x.(HTTPClientConfig)
 	return h.AuthType == obj.AuthType &&
@@ -40,7 +67,21 @@ func (h *HTTPClientConfig) Matches(x interface{}) bool {
 		reflect.DeepEqual(h.Creds, obj.Creds) &&
 		h.RetryCount == obj.RetryCount &&
 		h.RetryWaitTime == obj.RetryWaitTime &&
-		h.RetryWaitMaxTime == obj.RetryWaitMaxTime
+		h.RetryWaitMaxTime == obj.RetryWaitMaxTime &&
+		(h.RetryCondition == nil) == (obj.RetryCondition == nil) &&
+		h.Timeout == obj.Timeout &&
+		h.AttemptTimeout == obj.AttemptTimeout &&
+		h.TLSConfig == obj.TLSConfig &&
+		h.DialTimeout == obj.DialTimeout &&
+		h.TLSHandshakeTimeout == obj.TLSHandshakeTimeout &&
+		h.KeepAlive == obj.KeepAlive &&
+		h.MaxIdleConns == obj.MaxIdleConns &&
+		h.MaxIdleConnsPerHost == obj.MaxIdleConnsPerHost &&
+		h.IdleConnTimeout == obj.IdleConnTimeout &&
+		h.ProxyURL == obj.ProxyURL &&
+		len(h.Middlewares) == len(obj.Middlewares) &&
+		(h.Metrics == nil) == (obj.Metrics == nil) &&
+		(h.Logger == nil) == (obj.Logger == nil)
 }
 
 // String ...
@@ -56,6 +97,22 @@ type HTTPClient interface {
 	Delete(endpoint string) (int, *[]byte, error)
 }
 
+// Patcher is implemented by HTTP clients able to send PATCH requests, which Kafka Connect
+// requires to alter the offsets of connectors. It is not part of HTTPClient so that existing
+// implementations keep satisfying it.
+type Patcher interface {
+	Patch(endpoint string, body []byte) (int, *[]byte, error)
+}
+
+// Patch sends a PATCH request through c, or fails when c is not a Patcher.
+func Patch(c HTTPClient, endpoint string, body []byte) (int, *[]byte, error) {
+	p, ok := c.(Patcher)
+	if !ok {
+		return 0, &[]byte{}, fmt.Errorf("HTTP client %T does not support PATCH requests", c)
+	}
+	return p.Patch(endpoint, body)
+}
+
 // HTTPClientFactory ...
 type HTTPClientFactory interface {
 	Create(string, HTTPClientConfig) (HTTPClient, error)
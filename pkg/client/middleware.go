@@ -0,0 +1,253 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// Request is the transport independent representation of an HTTP call flowing
+// through a middleware chain.
+type Request struct {
+	Method   string
+	Endpoint string
+	Header   http.Header
+	Body     []byte
+	// Context cancels the request when done, nil means no cancellation.
+	Context context.Context
+}
+
+// Clone returns a copy of the request that can be safely modified, e.g. when retrying.
+func (r *Request) Clone() *Request {
+	c := *r
+	c.Header = r.Header.Clone()
+	if r.Body != nil {
+		c.Body = append([]byte{}, r.Body...)
+	}
+	return &c
+}
+
+// Response is the transport independent representation of an HTTP response flowing
+// back through a middleware chain.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// RoundTripFunc executes a Request and returns its Response.
+type RoundTripFunc func(*Request) (*Response, error)
+
+// Middleware decorates a RoundTripFunc with additional behaviour such as authentication,
+// retries, logging or metrics.
+type Middleware func(RoundTripFunc) RoundTripFunc
+
+// RetryConditionFunc reports whether a request should be retried given the outcome of
+// its last attempt.
+type RetryConditionFunc func(*Response, error) bool
+
+// MetricsRecorder receives one observation per HTTP attempt.
+type MetricsRecorder interface {
+	ObserveRequest(method string, endpoint string, status int, duration time.Duration, err error)
+}
+
+// Chain wraps transport with the given middlewares. The first middleware is the outermost
+// one, i.e. it sees the request first and the response last.
+func Chain(transport RoundTripFunc, middlewares ...Middleware) RoundTripFunc {
+	handler := transport
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// HeadersMiddleware adds the given headers to every request unless an outer middleware
+// already set them.
+func HeadersMiddleware(headers map[string]string) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *Request) (*Response, error) {
+			for key, value := range headers {
+				if req.Header.Get(key) == "" {
+					req.Header.Set(key, value)
+				}
+			}
+			return next(req)
+		}
+	}
+}
+
+// AuthMiddleware sets the Authorization header according to authType. Token auth expects
+// a "token" credential, basic auth expects "username" and "password".
+func AuthMiddleware(authType AuthType, creds map[string]string) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *Request) (*Response, error) {
+			switch authType {
+			case TokenAuth:
+				req.Header.Set("Authorization", "Bearer "+creds["token"])
+			case BasicAuth:
+				userPass := creds["username"] + ":" + creds["password"]
+				req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(userPass)))
+			default:
+				break
+			}
+			return next(req)
+		}
+	}
+}
+
+// TimeoutMiddleware cancels a request, including the retries and waits of the middlewares
+// after it, once timeout has elapsed.
+func TimeoutMiddleware(timeout time.Duration) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *Request) (*Response, error) {
+			parent := req.Context
+			if parent == nil {
+				parent = context.Background()
+			}
+			ctx, cancel := context.WithTimeout(parent, timeout)
+			defer cancel()
+			req.Context = ctx
+			return next(req)
+		}
+	}
+}
+
+// RetryMiddleware retries a request up to count times when any of the conditions returns
+// true for its response, or when it fails with an error and either its method is
+// idempotent or the connection could not be established. A POST, PUT or PATCH that may
+// have reached the server is not retried on errors. The wait between attempts doubles
+// from wait up to maxWait, and retries stop when the context of the request is done.
+func RetryMiddleware(count int, wait time.Duration, maxWait time.Duration, conditions ...RetryConditionFunc) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *Request) (*Response, error) {
+			resp, err := next(req.Clone())
+			for attempt := 1; attempt <= count && shouldRetry(req, resp, err, conditions); attempt++ {
+				if !sleep(req.Context, backoff(attempt, wait, maxWait)) {
+					break
+				}
+				resp, err = next(req.Clone())
+			}
+			return resp, err
+		}
+	}
+}
+
+func shouldRetry(req *Request, resp *Response, err error, conditions []RetryConditionFunc) bool {
+	if req.Context != nil && req.Context.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return idempotent(req.Method) || connectionError(err)
+	}
+	for _, condition := range conditions {
+		if condition != nil && condition(resp, err) {
+			return true
+		}
+	}
+	return false
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// connectionError tells whether err happened while connecting, before anything was sent.
+func connectionError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// sleep waits for d unless ctx is done first, and tells whether it waited.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if ctx == nil {
+		time.Sleep(d)
+		return true
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func backoff(attempt int, wait time.Duration, maxWait time.Duration) time.Duration {
+	d := wait << uint(attempt-1)
+	if maxWait > 0 && (d > maxWait || d < wait) {
+		return maxWait
+	}
+	return d
+}
+
+// LoggingMiddleware logs every request outcome. Headers and bodies are never logged as they
+// may carry credentials.
+func LoggingMiddleware(log logr.Logger) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *Request) (*Response, error) {
+			start := time.Now()
+			resp, err := next(req)
+			elapsed := time.Since(start)
+			if err != nil {
+				log.Error(err, "HTTP request failed", "method", req.Method, "endpoint", req.Endpoint, "duration", elapsed)
+				return resp, err
+			}
+			log.V(1).Info("HTTP request completed", "method", req.Method, "endpoint", req.Endpoint, "status", resp.StatusCode, "duration", elapsed)
+			return resp, err
+		}
+	}
+}
+
+// MetricsMiddleware reports every request outcome to recorder.
+func MetricsMiddleware(recorder MetricsRecorder) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *Request) (*Response, error) {
+			start := time.Now()
+			resp, err := next(req)
+			status := 0
+			if resp != nil {
+				status = resp.StatusCode
+			}
+			recorder.ObserveRequest(req.Method, req.Endpoint, status, time.Since(start), err)
+			return resp, err
+		}
+	}
+}
+
+// BuildMiddlewares returns the middleware chain described by config. The timeout comes
+// first so that it bounds the whole call, followed by the user supplied middlewares, retry,
+// metrics, logging, headers and auth.
+func BuildMiddlewares(config HTTPClientConfig) []Middleware {
+	var middlewares []Middleware
+	if config.Timeout > 0 {
+		middlewares = append(middlewares, TimeoutMiddleware(config.Timeout))
+	}
+	middlewares = append(middlewares, config.Middlewares...)
+
+	if config.RetryCount > 0 {
+		middlewares = append(middlewares, RetryMiddleware(config.RetryCount, config.RetryWaitTime, config.RetryWaitMaxTime, config.RetryCondition))
+	}
+
+	if config.Metrics != nil {
+		middlewares = append(middlewares, MetricsMiddleware(config.Metrics))
+	}
+
+	if config.Logger != nil {
+		middlewares = append(middlewares, LoggingMiddleware(config.Logger))
+	}
+
+	return append(middlewares,
+		HeadersMiddleware(config.Headers),
+		AuthMiddleware(config.AuthType, config.Creds),
+	)
+}
@@ -0,0 +1,167 @@
+// Package restyclient provides a client.HTTPClient implementation built on go-resty.
+package restyclient
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	resty "github.com/go-resty/resty/v2"
+	"github.com/walmartdigital/go-kaya/pkg/client"
+)
+
+// RestyClientFactory ...
+type RestyClientFactory struct{}
+
+// Create ...
+func (f RestyClientFactory) Create(url string, config client.HTTPClientConfig) (client.HTTPClient, error) {
+	r := NewRestyClient(url)
+
+	if r == nil {
+		return nil, errors.New("Error creating go-resty client")
+	}
+
+	transport, err := client.NewTransport(config)
+	if err != nil {
+		return nil, err
+	}
+
+	r.client.SetTransport(transport)
+	r.client.SetTimeout(config.AttemptTimeout)
+
+	r.Use(client.BuildMiddlewares(config)...)
+	return *r, nil
+}
+
+// RetryCondition adapts a go-resty retry condition to client.HTTPClientConfig.RetryCondition.
+// The response it is given carries the status and headers of the attempt but not its body.
+func RetryCondition(condition resty.RetryConditionFunc) client.RetryConditionFunc {
+	return func(resp *client.Response, err error) bool {
+		r := &resty.Response{}
+		if resp != nil {
+			r.RawResponse = &http.Response{StatusCode: resp.StatusCode, Header: resp.Header}
+		}
+		return condition(r, err)
+	}
+}
+
+// RestyClient ...
+type RestyClient struct {
+	client  *resty.Client
+	baseURL string
+	handler client.RoundTripFunc
+}
+
+// NewRestyClient ...
+func NewRestyClient(url string) *RestyClient {
+	r := RestyClient{
+		client:  resty.New(),
+		baseURL: url,
+	}
+	r.handler = r.execute
+	return &r
+}
+
+// Use wraps the client's transport with the given middlewares. The first middleware is
+// the outermost one.
+func (r *RestyClient) Use(middlewares ...client.Middleware) {
+	r.handler = client.Chain(r.handler, middlewares...)
+}
+
+// SetHeader ...
+func (r RestyClient) SetHeader(key string, value string) {
+	r.client.SetHeader(key, value)
+}
+
+// SetAuthToken ...
+func (r RestyClient) SetAuthToken(token string) {
+	r.client.SetAuthToken(token)
+}
+
+// SetRetryCount ...
+func (r RestyClient) SetRetryCount(count int) {
+	r.client.SetRetryCount(count)
+}
+
+// SetRetryWaitTime ...
+func (r RestyClient) SetRetryWaitTime(d time.Duration) {
+	r.client.SetRetryWaitTime(d)
+}
+
+// SetRetryMaxWaitTime ...
+func (r RestyClient) SetRetryMaxWaitTime(d time.Duration) {
+	r.client.SetRetryMaxWaitTime(d)
+}
+
+// AddRetryCondition ...
+func (r RestyClient) AddRetryCondition(condition resty.RetryConditionFunc) {
+	r.client.AddRetryCondition(condition)
+}
+
+// AddRetryCondition ...
+func (r RestyClient) SetBasicAuth(username string, password string) {
+	r.client.SetBasicAuth(username, password)
+}
+
+// Get ...
+func (r RestyClient) Get(endpoint string) (int, *[]byte, error) {
+	return r.do(http.MethodGet, endpoint, nil)
+}
+
+// Post ...
+func (r RestyClient) Post(endpoint string, body []byte) (int, *[]byte, error) {
+	return r.do(http.MethodPost, endpoint, body)
+}
+
+// Delete ...
+func (r RestyClient) Delete(endpoint string) (int, *[]byte, error) {
+	return r.do(http.MethodDelete, endpoint, nil)
+}
+
+// Put ...
+func (r RestyClient) Put(endpoint string, body []byte) (int, *[]byte, error) {
+	return r.do(http.MethodPut, endpoint, body)
+}
+
+// Patch ...
+func (r RestyClient) Patch(endpoint string, body []byte) (int, *[]byte, error) {
+	return r.do(http.MethodPatch, endpoint, body)
+}
+
+func (r RestyClient) do(method string, endpoint string, body []byte) (int, *[]byte, error) {
+	resp, err := r.handler(&client.Request{
+		Method:   method,
+		Endpoint: endpoint,
+		Header:   http.Header{},
+		Body:     body,
+	})
+	if resp == nil {
+		return 0, &[]byte{}, err
+	}
+	return resp.StatusCode, &resp.Body, err
+}
+
+func (r RestyClient) execute(req *client.Request) (*client.Response, error) {
+	request := r.client.R()
+	if req.Context != nil {
+		request.SetContext(req.Context)
+	}
+	for key, values := range req.Header {
+		for _, value := range values {
+			request.Header.Add(key, value)
+		}
+	}
+	if req.Body != nil {
+		request.SetBody(req.Body)
+	}
+
+	resp, err := request.Execute(req.Method, r.baseURL+req.Endpoint)
+	if resp == nil {
+		return nil, err
+	}
+	return &client.Response{
+		StatusCode: resp.StatusCode(),
+		Header:     resp.Header(),
+		Body:       resp.Body(),
+	}, err
+}
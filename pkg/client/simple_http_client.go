@@ -1,69 +1,142 @@
 package client
 
-// Replace this by go-resty
 import (
 	"bytes"
+	"context"
 	"io/ioutil"
 	"net/http"
 	"time"
-
-	"go.uber.org/zap"
 )
 
-var sugar *zap.SugaredLogger
+// DefaultTimeout is the timeout of the clients created by NewSimpleHTTPClient.
+const DefaultTimeout = 30 * time.Second
+
+// SimpleHTTPClientFactory creates HTTP clients built on the standard library only.
+type SimpleHTTPClientFactory struct{}
 
-func init() {
-	logger, _ := zap.NewProduction()
-	defer logger.Sync() // flushes buffer, if any
-	sugar = logger.Sugar()
+// Create ...
+func (f SimpleHTTPClientFactory) Create(url string, config HTTPClientConfig) (HTTPClient, error) {
+	hc, err := newConfiguredSimpleHTTPClient(url, config)
+	if err != nil {
+		return nil, err
+	}
+	return *hc, nil
 }
 
 // SimpleHTTPClient ...
 type SimpleHTTPClient struct {
 	baseURL string
+	client  *http.Client
+	handler RoundTripFunc
 }
 
-// NewSimpleHTTPClient ...
-func NewSimpleHTTPClient(url string) *SimpleHTTPClient {
-	hc := new(SimpleHTTPClient)
-	hc.baseURL = url
+// NewSimpleHTTPClient returns a client for url configured with config, or with a
+// DefaultTimeout when no config is given. It returns nil when config is invalid, use
+// SimpleHTTPClientFactory to get the error.
+func NewSimpleHTTPClient(url string, config ...HTTPClientConfig) *SimpleHTTPClient {
+	c := HTTPClientConfig{Timeout: DefaultTimeout}
+	if len(config) > 0 {
+		c = config[0]
+	}
+	hc, err := newConfiguredSimpleHTTPClient(url, c)
+	if err != nil {
+		return nil
+	}
 	return hc
 }
 
+func newConfiguredSimpleHTTPClient(url string, config HTTPClientConfig) (*SimpleHTTPClient, error) {
+	transport, err := NewTransport(config)
+	if err != nil {
+		return nil, err
+	}
+
+	hc := newSimpleHTTPClient(url, &http.Client{
+		Transport: transport,
+		Timeout:   config.AttemptTimeout,
+	})
+	hc.Use(BuildMiddlewares(config)...)
+	return hc, nil
+}
+
+func newSimpleHTTPClient(url string, client *http.Client) *SimpleHTTPClient {
+	hc := SimpleHTTPClient{
+		baseURL: url,
+		client:  client,
+	}
+	hc.handler = hc.execute
+	return &hc
+}
+
+// Use wraps the client's transport with the given middlewares. The first middleware is
+// the outermost one.
+func (shc *SimpleHTTPClient) Use(middlewares ...Middleware) {
+	shc.handler = Chain(shc.handler, middlewares...)
+}
+
 // Get ...
-func (shc SimpleHTTPClient) Get(endpoint string) (*string, *[]byte, error) {
-	return shc.sendRequest("GET", endpoint, nil)
+func (shc SimpleHTTPClient) Get(endpoint string) (int, *[]byte, error) {
+	return shc.sendRequest(http.MethodGet, endpoint, nil)
 }
 
 // Post ...
-func (shc SimpleHTTPClient) Post(endpoint string, content []byte) (*string, *[]byte, error) {
-	return shc.sendRequest("POST", endpoint, content)
+func (shc SimpleHTTPClient) Post(endpoint string, content []byte) (int, *[]byte, error) {
+	return shc.sendRequest(http.MethodPost, endpoint, content)
 }
 
 // Put ...
-func (shc SimpleHTTPClient) Put(endpoint string, content []byte) (*string, *[]byte, error) {
-	return shc.sendRequest("PUT", endpoint, content)
+func (shc SimpleHTTPClient) Put(endpoint string, content []byte) (int, *[]byte, error) {
+	return shc.sendRequest(http.MethodPut, endpoint, content)
+}
+
+// Patch ...
+func (shc SimpleHTTPClient) Patch(endpoint string, content []byte) (int, *[]byte, error) {
+	return shc.sendRequest(http.MethodPatch, endpoint, content)
 }
 
 // Delete ...
-func (shc SimpleHTTPClient) Delete(endpoint string) (*string, *[]byte, error) {
-	return shc.sendRequest("DELETE", endpoint, nil)
+func (shc SimpleHTTPClient) Delete(endpoint string) (int, *[]byte, error) {
+	return shc.sendRequest(http.MethodDelete, endpoint, nil)
 }
 
-func (shc SimpleHTTPClient) sendRequest(verb string, endpoint string, content []byte) (*string, *[]byte, error) {
-	req, err := http.NewRequest(verb, shc.baseURL+endpoint, bytes.NewBuffer(content))
-	req.Header.Set("Content-Type", "application/json")
+func (shc SimpleHTTPClient) sendRequest(verb string, endpoint string, content []byte) (int, *[]byte, error) {
+	resp, err := shc.handler(&Request{
+		Method:   verb,
+		Endpoint: endpoint,
+		Header:   http.Header{},
+		Body:     content,
+	})
+	if resp == nil {
+		return 0, &[]byte{}, err
+	}
+	return resp.StatusCode, &resp.Body, err
+}
 
-	client := &http.Client{
-		Timeout: time.Second * 30,
+func (shc SimpleHTTPClient) execute(req *Request) (*Response, error) {
+	ctx := req.Context
+	if ctx == nil {
+		ctx = context.Background()
 	}
-	resp, err := client.Do(req)
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, shc.baseURL+req.Endpoint, bytes.NewReader(req.Body))
 	if err != nil {
-		return nil, nil, err
+		return nil, err
+	}
+
+	httpReq.Header = req.Header.Clone()
+	if req.Body != nil && httpReq.Header.Get("Content-Type") == "" {
+		httpReq.Header.Set("Content-Type", "application/json")
 	}
-	defer resp.Body.Close()
 
-	body, _ := ioutil.ReadAll(resp.Body)
+	resp, err := shc.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
 
-	return &resp.Status, &body, nil
+	body, err := ioutil.ReadAll(resp.Body)
+	return &Response{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+	}, err
 }
@@ -0,0 +1,278 @@
+// Package connectors provides typed configs for common connectors, so that they can be
+// built without writing string maps by hand:
+//
+//	connector, err := connectors.ToConnector("logging", &connectors.ElasticsearchSink{
+//		Common:        connectors.Common{TasksMax: 5},
+//		Sink:          connectors.Sink{Topics: types.FlexList{"_ims.logs"}},
+//		ConnectionURL: types.FlexList{"http://elasticsearch:9200"},
+//		Linger:        connectors.Duration(time.Second),
+//		MaxRetries:    connectors.Int(0),
+//		KeyIgnore:     true,
+//	})
+//
+// Fields are mapped to config keys with `config` struct tags. Settings whose Kafka Connect
+// default is not the zero value of their type are pointers, set with Bool, Int and
+// Duration, so that zero values such as MaxRetries above are written too. Keys without a
+// field are kept in Extra, and configs read with FromConnector or Unmarshal are written
+// back unchanged.
+// Chains of single message transforms are built with NewChain and set with SetTransforms.
+package connectors
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/walmartdigital/go-kaya/pkg/kafkaconnect"
+	"github.com/walmartdigital/go-kaya/pkg/utils/types"
+)
+
+// Config is implemented by typed connector configs. Types embedding Common implement it
+// by defining Class.
+type Config interface {
+	// Class returns the connector class written when Common.ConnectorClass is empty.
+	Class() string
+	common() *Common
+}
+
+// Common holds the settings shared by every connector.
+type Common struct {
+	// ConnectorClass defaults to the Class of the config.
+	ConnectorClass string        `config:"connector.class"`
+	TasksMax       types.FlexInt `config:"tasks.max"`
+	KeyConverter   string        `config:"key.converter"`
+	ValueConverter string        `config:"value.converter"`
+	Errors         ErrorHandling
+	// Extra holds the keys without a field, and the values their field cannot hold, such as
+	// config provider placeholders in numeric fields. Extra values take precedence over
+	// fields.
+	Extra map[string]string `config:"-"`
+
+	// raw holds the values read by Unmarshal, so that they are written back as they were
+	// unless their field changed.
+	raw map[string]string
+}
+
+func (c *Common) common() *Common {
+	return c
+}
+
+// Sink holds the settings shared by sink connectors.
+type Sink struct {
+	Topics          types.FlexList `config:"topics"`
+	TopicsRegex     string         `config:"topics.regex"`
+	DeadLetterQueue DeadLetterQueue
+}
+
+// placeholderPattern matches config provider references, such as ${file:/secrets:password}.
+var placeholderPattern = regexp.MustCompile(`^\$\{[^}]*\}$`)
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// Bool returns a pointer to b, for optional fields such as JDBCSource.ValidateNonNull.
+func Bool(b bool) *types.FlexBool {
+	v := types.FlexBool(b)
+	return &v
+}
+
+// Int returns a pointer to n, for optional fields such as ElasticsearchSink.MaxRetries.
+func Int(n int) *types.FlexInt {
+	v := types.FlexInt(n)
+	return &v
+}
+
+// Duration returns a pointer to d, for optional fields such as ElasticsearchSink.Linger.
+func Duration(d time.Duration) *types.FlexDuration {
+	v := types.FlexDuration(d)
+	return &v
+}
+
+// ToConnector returns a connector called name with the config c.
+func ToConnector(name string, c Config) (kafkaconnect.Connector, error) {
+	config, err := Marshal(c)
+	if err != nil {
+		return kafkaconnect.Connector{}, err
+	}
+	return kafkaconnect.Connector{Name: name, Config: config}, nil
+}
+
+// FromConnector reads the config of connector into c.
+func FromConnector(connector kafkaconnect.Connector, c Config) error {
+	return Unmarshal(connector.Config, c)
+}
+
+// Marshal returns the Kafka Connect config of c. Fields holding their zero value and nil
+// pointer fields are omitted, unless Unmarshal read that value. Pointer fields that are set
+// are always written.
+func Marshal(c Config) (map[string]string, error) {
+	common := c.common()
+	config := make(map[string]string)
+	err := walk(reflect.ValueOf(c).Elem(), func(key string, field reflect.Value) error {
+		value, err := format(field)
+		if err != nil {
+			return fmt.Errorf("Invalid value for '%s': %s", key, err.Error())
+		}
+		if raw, ok := common.raw[key]; ok && same(field.Type(), raw, value) {
+			value = raw
+		} else if field.IsZero() {
+			return nil
+		}
+		config[key] = value
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if config["connector.class"] == "" {
+		config["connector.class"] = c.Class()
+	}
+	for k, v := range common.Extra {
+		config[k] = v
+	}
+	return config, nil
+}
+
+// Unmarshal reads config into c. Keys without a field are stored in Extra. It fails when
+// a value cannot be parsed, unless it is a config provider placeholder, or when the
+// connector class is not the class of c.
+func Unmarshal(config map[string]string, c Config) error {
+	if class, ok := config["connector.class"]; ok && !sameClass(c.Class(), class) {
+		return fmt.Errorf("Connector class %s is not %s", class, c.Class())
+	}
+
+	common := c.common()
+	common.raw = make(map[string]string)
+	common.Extra = make(map[string]string)
+	known := make(map[string]bool)
+	err := walk(reflect.ValueOf(c).Elem(), func(key string, field reflect.Value) error {
+		known[key] = true
+		value, ok := config[key]
+		if !ok {
+			field.Set(reflect.Zero(field.Type()))
+			return nil
+		}
+		if err := parse(value, field); err != nil {
+			if placeholderPattern.MatchString(value) {
+				common.Extra[key] = value
+				return nil
+			}
+			return fmt.Errorf("Invalid value for '%s': %s", key, err.Error())
+		}
+		common.raw[key] = value
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for k, v := range config {
+		if !known[k] {
+			common.Extra[k] = v
+		}
+	}
+	return nil
+}
+
+// walk calls fn with the config key and the value of every tagged field of v, including
+// the fields of embedded and untagged structs.
+func walk(v reflect.Value, fn func(key string, field reflect.Value) error) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Type.Kind() == reflect.Struct && f.PkgPath == "" && (f.Anonymous || f.Tag.Get("config") == "") {
+			if err := walk(v.Field(i), fn); err != nil {
+				return err
+			}
+			continue
+		}
+		key := f.Tag.Get("config")
+		if key == "" || key == "-" {
+			continue
+		}
+		if err := fn(key, v.Field(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func format(field reflect.Value) (string, error) {
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return "", nil
+		}
+		return format(field.Elem())
+	}
+	if field.Type().Implements(textMarshalerType) {
+		text, err := field.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+	switch field.Kind() {
+	case reflect.String:
+		return field.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(field.Int(), 10), nil
+	case reflect.Bool:
+		return strconv.FormatBool(field.Bool()), nil
+	}
+	return "", fmt.Errorf("unsupported type %s", field.Type())
+}
+
+func parse(value string, field reflect.Value) error {
+	if field.Kind() == reflect.Ptr {
+		elem := reflect.New(field.Type().Elem())
+		if err := parse(value, elem.Elem()); err != nil {
+			return err
+		}
+		field.Set(elem)
+		return nil
+	}
+	if u, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(value))
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(strings.TrimSpace(value), 10, field.Type().Bits())
+		if err != nil {
+			return errors.New("must be an int")
+		}
+		field.SetInt(i)
+	case reflect.Bool:
+		switch value = strings.TrimSpace(value); {
+		case strings.EqualFold(value, "true"):
+			field.SetBool(true)
+		case strings.EqualFold(value, "false"):
+			field.SetBool(false)
+		default:
+			return errors.New("must be true or false")
+		}
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
+
+// same reports whether the raw value read by Unmarshal still holds value, such as " 5"
+// and "5", so that Marshal does not rewrite untouched values.
+func same(t reflect.Type, raw string, value string) bool {
+	field := reflect.New(t).Elem()
+	if err := parse(raw, field); err != nil {
+		return false
+	}
+	formatted, err := format(field)
+	return err == nil && formatted == value
+}
+
+// sameClass reports whether class names the connector class want, either fully qualified
+// or by its simple name.
+func sameClass(want string, class string) bool {
+	return class == want || strings.HasSuffix(want, "."+class)
+}
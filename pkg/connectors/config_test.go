@@ -0,0 +1,185 @@
+package connectors_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/walmartdigital/go-kaya/pkg/connectors"
+	"github.com/walmartdigital/go-kaya/pkg/kafkaconnect"
+	"github.com/walmartdigital/go-kaya/pkg/utils/types"
+	"github.com/walmartdigital/go-kaya/pkg/validator"
+)
+
+func TestAll(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Connectors")
+}
+
+var _ = Describe("Typed configs", func() {
+	It("should build connectors without string maps", func() {
+		connector, err := connectors.ToConnector("logging", &connectors.ElasticsearchSink{
+			Common:        connectors.Common{TasksMax: 5},
+			Sink:          connectors.Sink{Topics: types.FlexList{"_ims.logs", "_dumblogger.logs"}},
+			ConnectionURL: types.FlexList{"http://elasticsearch:9200"},
+			TopicIndexMap: types.FlexMap{"_ims.logs": "ims", "_dumblogger.logs": "dumblogger"},
+			Linger:        connectors.Duration(time.Second),
+			MaxRetries:    connectors.Int(0),
+			KeyIgnore:     true,
+		})
+		Expect(err).To(BeNil())
+		Expect(connector).To(Equal(kafkaconnect.Connector{
+			Name: "logging",
+			Config: map[string]string{
+				"connector.class": "io.confluent.connect.elasticsearch.ElasticsearchSinkConnector",
+				"tasks.max":       "5",
+				"topics":          "_ims.logs,_dumblogger.logs",
+				"connection.url":  "http://elasticsearch:9200",
+				"topic.index.map": "_dumblogger.logs:dumblogger,_ims.logs:ims",
+				"linger.ms":       "1000",
+				"max.retries":     "0",
+				"key.ignore":      "true",
+			},
+		}))
+		connector.Config["name"] = connector.Name
+		Expect(validator.New().Validate(connector.Config).Violations).To(BeEmpty())
+	})
+
+	It("should read configs and write them back unchanged", func() {
+		config := map[string]string{
+			"connector.class":       "JdbcSourceConnector",
+			"tasks.max":             " 2",
+			"connection.url":        "jdbc:postgresql://db:5432/shop",
+			"connection.password":   "${file:/secrets/db.properties:password}",
+			"mode":                  "timestamp",
+			"timestamp.column.name": "updated_at, created_at",
+			"topic.prefix":          "shop-",
+			"poll.interval.ms":      "${file:/config/jdbc.properties:poll}",
+			"validate.non.null":     "FALSE",
+			"transforms":            "route",
+			"transforms.route.type": "org.apache.kafka.connect.transforms.RegexRouter",
+		}
+
+		var source connectors.JDBCSource
+		Expect(connectors.Unmarshal(config, &source)).To(Succeed())
+		Expect(int(source.TasksMax)).To(Equal(2))
+		Expect(string(source.ConnectionPassword)).To(Equal("${file:/secrets/db.properties:password}"))
+		Expect(source.TimestampColumnName).To(Equal(types.FlexList{"updated_at", "created_at"}))
+		Expect(source.PollInterval).To(BeNil())
+		Expect(source.ValidateNonNull).To(Equal(connectors.Bool(false)))
+		Expect(source.Extra).To(Equal(map[string]string{
+			"poll.interval.ms":      "${file:/config/jdbc.properties:poll}",
+			"transforms":            "route",
+			"transforms.route.type": "org.apache.kafka.connect.transforms.RegexRouter",
+		}))
+
+		back, err := connectors.Marshal(&source)
+		Expect(err).To(BeNil())
+		Expect(back).To(Equal(config))
+
+		source.TasksMax = 4
+		source.BatchMaxRows = 500
+		back, err = connectors.Marshal(&source)
+		Expect(err).To(BeNil())
+		Expect(back["tasks.max"]).To(Equal("4"))
+		Expect(back["batch.max.rows"]).To(Equal("500"))
+		Expect(back["validate.non.null"]).To(Equal("FALSE"))
+	})
+
+	It("should write zero values of settings with other defaults once they are set", func() {
+		source := connectors.JDBCSource{JDBC: connectors.JDBC{ConnectionURL: "jdbc:postgresql://db:5432/shop"}}
+		config, err := connectors.Marshal(&source)
+		Expect(err).To(BeNil())
+		Expect(config).NotTo(HaveKey("validate.non.null"))
+
+		source.ValidateNonNull = connectors.Bool(false)
+		config, err = connectors.Marshal(&source)
+		Expect(err).To(BeNil())
+		Expect(config).To(HaveKeyWithValue("validate.non.null", "false"))
+
+		source.PollInterval = connectors.Duration(0)
+		config, err = connectors.Marshal(&source)
+		Expect(err).To(BeNil())
+		Expect(config).To(HaveKeyWithValue("poll.interval.ms", "0"))
+		var readSource connectors.JDBCSource
+		Expect(connectors.Unmarshal(config, &readSource)).To(Succeed())
+		Expect(readSource.PollInterval).To(Equal(connectors.Duration(0)))
+
+		sink := connectors.JDBCSink{MaxRetries: connectors.Int(0), RetryBackoff: connectors.Duration(0)}
+		config, err = connectors.Marshal(&sink)
+		Expect(err).To(BeNil())
+		Expect(config).To(HaveKeyWithValue("max.retries", "0"))
+		Expect(config).To(HaveKeyWithValue("retry.backoff.ms", "0"))
+
+		var es connectors.ElasticsearchSink
+		Expect(connectors.Unmarshal(map[string]string{"max.retries": "0"}, &es)).To(Succeed())
+		Expect(es.MaxRetries).To(Equal(connectors.Int(0)))
+		Expect(es.RetryBackoff).To(BeNil())
+		es.MaxRetries = nil
+		config, err = connectors.Marshal(&es)
+		Expect(err).To(BeNil())
+		Expect(config).NotTo(HaveKey("max.retries"))
+
+		es.ConnectionTimeout = connectors.Duration(0)
+		es.ReadTimeout = connectors.Duration(0)
+		es.Errors.RetryDelayMax = connectors.Duration(0)
+		config, err = connectors.Marshal(&es)
+		Expect(err).To(BeNil())
+		Expect(config).To(HaveKeyWithValue("connection.timeout.ms", "0"))
+		Expect(config).To(HaveKeyWithValue("read.timeout.ms", "0"))
+		Expect(config).To(HaveKeyWithValue("errors.retry.delay.max.ms", "0"))
+		var readES connectors.ElasticsearchSink
+		Expect(connectors.Unmarshal(config, &readES)).To(Succeed())
+		Expect(readES.ConnectionTimeout).To(Equal(connectors.Duration(0)))
+		Expect(readES.ReadTimeout).To(Equal(connectors.Duration(0)))
+		Expect(readES.Errors.RetryDelayMax).To(Equal(connectors.Duration(0)))
+
+		config, err = connectors.ErrorHandling{RetryDelayMax: connectors.Duration(0)}.Render()
+		Expect(err).To(BeNil())
+		Expect(config).To(Equal(map[string]string{"errors.retry.delay.max.ms": "0"}))
+	})
+
+	It("should read connectors of every kind", func() {
+		var sink connectors.JDBCSink
+		Expect(connectors.FromConnector(kafkaconnect.Connector{Name: "orders", Config: map[string]string{
+			"connector.class": validator.JDBCSinkClass,
+			"topics":          "orders",
+			"connection.url":  "jdbc:postgresql://db:5432/shop",
+			"insert.mode":     "upsert",
+			"pk.mode":         "record_key",
+			"auto.create":     "true",
+		}}, &sink)).To(Succeed())
+		Expect(sink.Topics).To(Equal(types.FlexList{"orders"}))
+		Expect(sink.ConnectionURL).To(Equal("jdbc:postgresql://db:5432/shop"))
+		Expect(bool(sink.AutoCreate)).To(BeTrue())
+		Expect(sink.Extra).To(BeEmpty())
+
+		var s3 connectors.S3Sink
+		Expect(connectors.Unmarshal(map[string]string{
+			"topics":         "logs",
+			"s3.bucket.name": "logs-archive",
+			"flush.size":     "1000",
+		}, &s3)).To(Succeed())
+		Expect(int(s3.FlushSize)).To(Equal(1000))
+
+		config, err := connectors.Marshal(&s3)
+		Expect(err).To(BeNil())
+		Expect(config["connector.class"]).To(Equal(validator.S3SinkClass))
+	})
+
+	It("should reject values their field cannot hold", func() {
+		var es connectors.ElasticsearchSink
+		err := connectors.Unmarshal(map[string]string{"batch.size": "lots"}, &es)
+		Expect(err).To(MatchError("Invalid value for 'batch.size': must be an int"))
+
+		err = connectors.Unmarshal(map[string]string{"key.ignore": "yes"}, &es)
+		Expect(err).To(MatchError("Invalid value for 'key.ignore': must be true or false"))
+	})
+
+	It("should reject configs of other connectors", func() {
+		var es connectors.ElasticsearchSink
+		err := connectors.Unmarshal(map[string]string{"connector.class": validator.S3SinkClass}, &es)
+		Expect(err).To(MatchError("Connector class io.confluent.connect.s3.S3SinkConnector is not io.confluent.connect.elasticsearch.ElasticsearchSinkConnector"))
+	})
+})
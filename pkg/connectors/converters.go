@@ -0,0 +1,128 @@
+package connectors
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Classes of common converters.
+const (
+	JSONConverterClass      = "org.apache.kafka.connect.json.JsonConverter"
+	StringConverterClass    = "org.apache.kafka.connect.storage.StringConverter"
+	ByteArrayConverterClass = "org.apache.kafka.connect.converters.ByteArrayConverter"
+	AvroConverterClass      = "io.confluent.connect.avro.AvroConverter"
+)
+
+// Converter is a key or value converter, rendered as the key.converter or value.converter
+// key and the keys prefixed with it.
+type Converter struct {
+	Class string
+	// Config holds the properties of the converter, without the key.converter. or
+	// value.converter. prefix.
+	Config map[string]string
+}
+
+// JSONConverter returns a converter of JSON records, with their schema embedded when
+// schemas is true.
+func JSONConverter(schemas bool) Converter {
+	return Converter{Class: JSONConverterClass, Config: map[string]string{
+		"schemas.enable": strconv.FormatBool(schemas),
+	}}
+}
+
+// AvroConverter returns a converter of Avro records, with their schemas stored in the
+// schema registry at registryURL.
+func AvroConverter(registryURL string) Converter {
+	return Converter{Class: AvroConverterClass, Config: map[string]string{
+		"schema.registry.url": registryURL,
+	}}
+}
+
+// StringConverter returns a converter of records as strings.
+func StringConverter() Converter {
+	return Converter{Class: StringConverterClass}
+}
+
+// ByteArrayConverter returns a converter passing records through as bytes.
+func ByteArrayConverter() Converter {
+	return Converter{Class: ByteArrayConverterClass}
+}
+
+// With returns a copy of c with the property key set to value.
+func (c Converter) With(key string, value string) Converter {
+	config := make(map[string]string, len(c.Config)+1)
+	for k, v := range c.Config {
+		config[k] = v
+	}
+	config[key] = value
+	c.Config = config
+	return c
+}
+
+// converterKey returns the key setting the converter of target.
+func converterKey(target Target) string {
+	if target == Key {
+		return "key.converter"
+	}
+	return "value.converter"
+}
+
+// Render returns the config keys setting c as the converter of target. A converter without
+// class renders no keys, leaving the converter of the worker.
+func (c Converter) Render(target Target) map[string]string {
+	config := make(map[string]string)
+	if c.Class == "" {
+		return config
+	}
+	key := converterKey(target)
+	for k, v := range c.Config {
+		config[key+"."+k] = v
+	}
+	config[key] = c.Class
+	return config
+}
+
+// ParseConverter reads the converter of target from config.
+func ParseConverter(config map[string]string, target Target) Converter {
+	key := converterKey(target)
+	return Converter{Class: config[key], Config: properties(config, key+".")}
+}
+
+// Converters returns the key and value converters set on c. Their class is empty when c
+// uses the converters of the worker.
+func (c *Common) Converters() (Converter, Converter) {
+	key := ParseConverter(c.Extra, Key)
+	key.Class = c.KeyConverter
+	value := ParseConverter(c.Extra, Value)
+	value.Class = c.ValueConverter
+	return key, value
+}
+
+// SetKeyConverter replaces the key converter of c.
+func (c *Common) SetKeyConverter(converter Converter) {
+	c.KeyConverter = c.setConverter(converter, Key)
+}
+
+// SetValueConverter replaces the value converter of c.
+func (c *Common) SetValueConverter(converter Converter) {
+	c.ValueConverter = c.setConverter(converter, Value)
+}
+
+// setConverter replaces the properties of the converter of target held in Extra and
+// returns the class of converter.
+func (c *Common) setConverter(converter Converter, target Target) string {
+	prefix := converterKey(target) + "."
+	extra := make(map[string]string, len(c.Extra)+len(converter.Config))
+	for k, v := range c.Extra {
+		if !strings.HasPrefix(k, prefix) {
+			extra[k] = v
+		}
+	}
+	if converter.Class != "" {
+		for k, v := range converter.Config {
+			extra[prefix+k] = v
+		}
+	}
+	c.Extra = extra
+	return converter.Class
+}
@@ -0,0 +1,38 @@
+package connectors
+
+import (
+	"github.com/walmartdigital/go-kaya/pkg/utils/types"
+	"github.com/walmartdigital/go-kaya/pkg/validator"
+)
+
+// ElasticsearchSink is the config of the Confluent Elasticsearch sink connector.
+// ConnectionTimeout, ReadTimeout, Linger, MaxRetries and RetryBackoff are pointers, as they
+// default to 1s, 3s, 1ms, 5 and 100ms.
+type ElasticsearchSink struct {
+	Common
+	Sink
+	ConnectionURL                types.FlexList      `config:"connection.url"`
+	ConnectionUsername           string              `config:"connection.username"`
+	ConnectionPassword           types.Password      `config:"connection.password"`
+	ConnectionTimeout            *types.FlexDuration `config:"connection.timeout.ms"`
+	ReadTimeout                  *types.FlexDuration `config:"read.timeout.ms"`
+	TypeName                     string              `config:"type.name"`
+	TopicIndexMap                types.FlexMap       `config:"topic.index.map"`
+	KeyIgnore                    types.FlexBool      `config:"key.ignore"`
+	SchemaIgnore                 types.FlexBool      `config:"schema.ignore"`
+	WriteMethod                  string              `config:"write.method"`
+	BatchSize                    types.FlexInt       `config:"batch.size"`
+	MaxBufferedRecords           types.FlexInt       `config:"max.buffered.records"`
+	MaxInFlightRequests          types.FlexInt       `config:"max.in.flight.requests"`
+	Linger                       *types.FlexDuration `config:"linger.ms"`
+	FlushTimeout                 types.FlexDuration  `config:"flush.timeout.ms"`
+	MaxRetries                   *types.FlexInt      `config:"max.retries"`
+	RetryBackoff                 *types.FlexDuration `config:"retry.backoff.ms"`
+	BehaviorOnNullValues         string              `config:"behavior.on.null.values"`
+	BehaviorOnMalformedDocuments string              `config:"behavior.on.malformed.documents"`
+}
+
+// Class ...
+func (*ElasticsearchSink) Class() string {
+	return validator.ElasticsearchSinkClass
+}
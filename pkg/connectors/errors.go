@@ -0,0 +1,69 @@
+package connectors
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/walmartdigital/go-kaya/pkg/utils/types"
+)
+
+// Values of errors.tolerance.
+const (
+	// ToleranceNone fails the task on the first error. This is the default.
+	ToleranceNone = "none"
+	// ToleranceAll skips records that fail conversion or transformation.
+	ToleranceAll = "all"
+)
+
+// RetryForever is the errors.retry.timeout retrying failed operations forever.
+const RetryForever = types.FlexDuration(-time.Millisecond)
+
+// ErrorHandling holds the error handling settings of every connector. RetryDelayMax is a
+// pointer, as it defaults to 1m.
+type ErrorHandling struct {
+	Tolerance          string              `config:"errors.tolerance"`
+	RetryTimeout       types.FlexDuration  `config:"errors.retry.timeout"`
+	RetryDelayMax      *types.FlexDuration `config:"errors.retry.delay.max.ms"`
+	LogEnable          types.FlexBool      `config:"errors.log.enable"`
+	LogIncludeMessages types.FlexBool      `config:"errors.log.include.messages"`
+}
+
+// DeadLetterQueue holds the dead letter queue settings of sink connectors, to which the
+// records tolerated with ToleranceAll are sent.
+type DeadLetterQueue struct {
+	TopicName              string         `config:"errors.deadletterqueue.topic.name"`
+	TopicReplicationFactor types.FlexInt  `config:"errors.deadletterqueue.topic.replication.factor"`
+	ContextHeadersEnable   types.FlexBool `config:"errors.deadletterqueue.context.headers.enable"`
+}
+
+// Render returns the config keys of the settings of e that are set.
+func (e ErrorHandling) Render() (map[string]string, error) {
+	return render(reflect.ValueOf(e))
+}
+
+// Render returns the config keys of the settings of d that are set.
+func (d DeadLetterQueue) Render() (map[string]string, error) {
+	return render(reflect.ValueOf(d))
+}
+
+// render returns the config keys of the fields of v holding other values than their zero
+// value.
+func render(v reflect.Value) (map[string]string, error) {
+	config := make(map[string]string)
+	err := walk(v, func(key string, field reflect.Value) error {
+		if field.IsZero() {
+			return nil
+		}
+		value, err := format(field)
+		if err != nil {
+			return fmt.Errorf("Invalid value for '%s': %s", key, err.Error())
+		}
+		config[key] = value
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
@@ -0,0 +1,101 @@
+package connectors_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/walmartdigital/go-kaya/pkg/connectors"
+	"github.com/walmartdigital/go-kaya/pkg/utils/types"
+	"github.com/walmartdigital/go-kaya/pkg/validator"
+)
+
+var _ = Describe("Converters and error handling", func() {
+	It("should render error handling and dead letter queues", func() {
+		errors := connectors.ErrorHandling{
+			Tolerance:          connectors.ToleranceAll,
+			RetryTimeout:       connectors.RetryForever,
+			RetryDelayMax:      connectors.Duration(time.Minute),
+			LogEnable:          true,
+			LogIncludeMessages: true,
+		}
+		config, err := errors.Render()
+		Expect(err).To(BeNil())
+		Expect(config).To(Equal(map[string]string{
+			"errors.tolerance":            "all",
+			"errors.retry.timeout":        "-1",
+			"errors.retry.delay.max.ms":   "60000",
+			"errors.log.enable":           "true",
+			"errors.log.include.messages": "true",
+		}))
+
+		config, err = connectors.DeadLetterQueue{TopicName: "logs-dlq", ContextHeadersEnable: true}.Render()
+		Expect(err).To(BeNil())
+		Expect(config).To(Equal(map[string]string{
+			"errors.deadletterqueue.topic.name":             "logs-dlq",
+			"errors.deadletterqueue.context.headers.enable": "true",
+		}))
+	})
+
+	It("should set converters and error handling on typed configs", func() {
+		es := &connectors.ElasticsearchSink{
+			Common: connectors.Common{
+				Errors: connectors.ErrorHandling{Tolerance: connectors.ToleranceAll, LogEnable: true},
+			},
+			Sink: connectors.Sink{
+				Topics:          types.FlexList{"logs"},
+				DeadLetterQueue: connectors.DeadLetterQueue{TopicName: "logs-dlq", TopicReplicationFactor: 3},
+			},
+			ConnectionURL: types.FlexList{"http://elasticsearch:9200"},
+		}
+		es.SetKeyConverter(connectors.StringConverter())
+		es.SetValueConverter(connectors.AvroConverter("http://schema-registry:8081"))
+
+		connector, err := connectors.ToConnector("logging", es)
+		Expect(err).To(BeNil())
+		Expect(connector.Config).To(Equal(map[string]string{
+			"connector.class":                     validator.ElasticsearchSinkClass,
+			"topics":                              "logs",
+			"connection.url":                      "http://elasticsearch:9200",
+			"key.converter":                       "org.apache.kafka.connect.storage.StringConverter",
+			"value.converter":                     "io.confluent.connect.avro.AvroConverter",
+			"value.converter.schema.registry.url": "http://schema-registry:8081",
+			"errors.tolerance":                    "all",
+			"errors.log.enable":                   "true",
+			"errors.deadletterqueue.topic.name":   "logs-dlq",
+			"errors.deadletterqueue.topic.replication.factor": "3",
+		}))
+		connector.Config["name"] = connector.Name
+		Expect(validator.New().Validate(connector.Config).Violations).To(BeEmpty())
+
+		var back connectors.ElasticsearchSink
+		Expect(connectors.FromConnector(connector, &back)).To(Succeed())
+		Expect(back.DeadLetterQueue.TopicName).To(Equal("logs-dlq"))
+		key, value := back.Converters()
+		Expect(key).To(Equal(connectors.Converter{Class: connectors.StringConverterClass, Config: map[string]string{}}))
+		Expect(value).To(Equal(connectors.AvroConverter("http://schema-registry:8081")))
+
+		back.SetValueConverter(connectors.JSONConverter(false))
+		config, err := connectors.Marshal(&back)
+		Expect(err).To(BeNil())
+		Expect(config).NotTo(HaveKey("value.converter.schema.registry.url"))
+		Expect(config).To(HaveKeyWithValue("value.converter.schemas.enable", "false"))
+
+		back.SetValueConverter(connectors.Converter{})
+		config, err = connectors.Marshal(&back)
+		Expect(err).To(BeNil())
+		Expect(config).NotTo(HaveKey("value.converter"))
+		Expect(config).NotTo(HaveKey("value.converter.schemas.enable"))
+	})
+
+	It("should render converters of records keys and values", func() {
+		converter := connectors.JSONConverter(true).With("decimal.format", "NUMERIC")
+		Expect(converter.Render(connectors.Key)).To(Equal(map[string]string{
+			"key.converter":                "org.apache.kafka.connect.json.JsonConverter",
+			"key.converter.schemas.enable": "true",
+			"key.converter.decimal.format": "NUMERIC",
+		}))
+		Expect(connectors.ParseConverter(converter.Render(connectors.Value), connectors.Value)).To(Equal(converter))
+		Expect(connectors.ByteArrayConverter().Render(connectors.Value)).To(HaveLen(1))
+	})
+})
@@ -0,0 +1,58 @@
+package connectors
+
+import (
+	"github.com/walmartdigital/go-kaya/pkg/utils/types"
+	"github.com/walmartdigital/go-kaya/pkg/validator"
+)
+
+// JDBC holds the connection settings shared by the JDBC source and sink connectors.
+type JDBC struct {
+	ConnectionURL      string         `config:"connection.url"`
+	ConnectionUser     string         `config:"connection.user"`
+	ConnectionPassword types.Password `config:"connection.password"`
+}
+
+// JDBCSource is the config of the Confluent JDBC source connector. PollInterval and
+// ValidateNonNull are pointers, as they default to 5s and true.
+type JDBCSource struct {
+	Common
+	JDBC
+	Mode                   string              `config:"mode"`
+	IncrementingColumnName string              `config:"incrementing.column.name"`
+	TimestampColumnName    types.FlexList      `config:"timestamp.column.name"`
+	TableWhitelist         types.FlexList      `config:"table.whitelist"`
+	TableBlacklist         types.FlexList      `config:"table.blacklist"`
+	Query                  string              `config:"query"`
+	TopicPrefix            string              `config:"topic.prefix"`
+	PollInterval           *types.FlexDuration `config:"poll.interval.ms"`
+	BatchMaxRows           types.FlexInt       `config:"batch.max.rows"`
+	ValidateNonNull        *types.FlexBool     `config:"validate.non.null"`
+}
+
+// Class ...
+func (*JDBCSource) Class() string {
+	return validator.JDBCSourceClass
+}
+
+// JDBCSink is the config of the Confluent JDBC sink connector. MaxRetries and
+// RetryBackoff are pointers, as they default to 10 and 3s.
+type JDBCSink struct {
+	Common
+	Sink
+	JDBC
+	InsertMode      string              `config:"insert.mode"`
+	PKMode          string              `config:"pk.mode"`
+	PKFields        types.FlexList      `config:"pk.fields"`
+	TableNameFormat string              `config:"table.name.format"`
+	AutoCreate      types.FlexBool      `config:"auto.create"`
+	AutoEvolve      types.FlexBool      `config:"auto.evolve"`
+	DeleteEnabled   types.FlexBool      `config:"delete.enabled"`
+	BatchSize       types.FlexInt       `config:"batch.size"`
+	MaxRetries      *types.FlexInt      `config:"max.retries"`
+	RetryBackoff    *types.FlexDuration `config:"retry.backoff.ms"`
+}
+
+// Class ...
+func (*JDBCSink) Class() string {
+	return validator.JDBCSinkClass
+}
@@ -0,0 +1,30 @@
+package connectors
+
+import (
+	"github.com/walmartdigital/go-kaya/pkg/utils/types"
+	"github.com/walmartdigital/go-kaya/pkg/validator"
+)
+
+// S3Sink is the config of the Confluent S3 sink connector.
+type S3Sink struct {
+	Common
+	Sink
+	S3BucketName      string             `config:"s3.bucket.name"`
+	S3Region          string             `config:"s3.region"`
+	S3PartSize        types.FlexInt      `config:"s3.part.size"`
+	FlushSize         types.FlexInt      `config:"flush.size"`
+	RotateInterval    types.FlexDuration `config:"rotate.interval.ms"`
+	StorageClass      string             `config:"storage.class"`
+	FormatClass       string             `config:"format.class"`
+	PartitionerClass  string             `config:"partitioner.class"`
+	TopicsDir         string             `config:"topics.dir"`
+	PathFormat        string             `config:"path.format"`
+	PartitionDuration types.FlexDuration `config:"partition.duration.ms"`
+	Locale            string             `config:"locale"`
+	Timezone          string             `config:"timezone"`
+}
+
+// Class ...
+func (*S3Sink) Class() string {
+	return validator.S3SinkClass
+}
@@ -0,0 +1,382 @@
+package connectors
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/walmartdigital/go-kaya/pkg/utils/types"
+)
+
+// Classes of the transforms and predicates shipped with Kafka Connect.
+const (
+	RegexRouterType       = "org.apache.kafka.connect.transforms.RegexRouter"
+	TimestampRouterType   = "org.apache.kafka.connect.transforms.TimestampRouter"
+	ExtractFieldKeyType   = "org.apache.kafka.connect.transforms.ExtractField$Key"
+	ExtractFieldValueType = "org.apache.kafka.connect.transforms.ExtractField$Value"
+	ReplaceFieldKeyType   = "org.apache.kafka.connect.transforms.ReplaceField$Key"
+	ReplaceFieldValueType = "org.apache.kafka.connect.transforms.ReplaceField$Value"
+
+	TopicNameMatchesType  = "org.apache.kafka.connect.transforms.predicates.TopicNameMatches"
+	HasHeaderKeyType      = "org.apache.kafka.connect.transforms.predicates.HasHeaderKey"
+	RecordIsTombstoneType = "org.apache.kafka.connect.transforms.predicates.RecordIsTombstone"
+)
+
+// Target selects the part of records a transform applies to.
+type Target string
+
+// Targets.
+const (
+	Key   Target = "$Key"
+	Value Target = "$Value"
+)
+
+// Transform is a single message transform, rendered as transforms.<Name>.* keys.
+type Transform struct {
+	Name string
+	Type string
+	// Predicate is the name of the predicate the transform applies on, if any.
+	Predicate string
+	// Negate applies the transform to records not matching Predicate.
+	Negate bool
+	// Config holds the properties of the transform, without the transforms.<Name>. prefix.
+	Config map[string]string
+}
+
+// Predicate is a condition on records that transforms apply on, as introduced by KIP-585.
+// It is rendered as predicates.<Name>.* keys.
+type Predicate struct {
+	Name string
+	Type string
+	// Config holds the properties of the predicate, without the predicates.<Name>. prefix.
+	Config map[string]string
+}
+
+// Chain is an ordered list of transforms and the predicates they use.
+type Chain struct {
+	Transforms []Transform
+	Predicates []Predicate
+}
+
+// spec lists the properties a known transform or predicate requires.
+type spec struct {
+	required []string
+	// oneOf lists properties of which at least one is required.
+	oneOf []string
+	check func(config map[string]string) string
+}
+
+var replaceFieldSpec = spec{
+	oneOf: []string{"exclude", "include", "renames", "blacklist", "whitelist"},
+	check: func(config map[string]string) string {
+		var renames types.FlexMap
+		if err := renames.UnmarshalText([]byte(config["renames"])); err != nil {
+			return fmt.Sprintf("invalid renames: %s", err.Error())
+		}
+		return ""
+	},
+}
+
+var knownTransforms = map[string]spec{
+	RegexRouterType:       {required: []string{"regex", "replacement"}},
+	TimestampRouterType:   {},
+	ExtractFieldKeyType:   {required: []string{"field"}},
+	ExtractFieldValueType: {required: []string{"field"}},
+	ReplaceFieldKeyType:   replaceFieldSpec,
+	ReplaceFieldValueType: replaceFieldSpec,
+}
+
+var knownPredicates = map[string]spec{
+	TopicNameMatchesType:  {required: []string{"pattern"}},
+	HasHeaderKeyType:      {required: []string{"name"}},
+	RecordIsTombstoneType: {},
+}
+
+// RegexRouter returns a transform renaming the topics matching regex to replacement, which
+// may refer to groups as $1.
+func RegexRouter(name string, regex string, replacement string) Transform {
+	return Transform{Name: name, Type: RegexRouterType, Config: map[string]string{
+		"regex":       regex,
+		"replacement": replacement,
+	}}
+}
+
+// TimestampRouter returns a transform renaming topics with the timestamp of records, such
+// as "${topic}-${timestamp}" and "yyyyMMdd". Empty formats use the defaults of Kafka Connect.
+func TimestampRouter(name string, topicFormat string, timestampFormat string) Transform {
+	config := make(map[string]string)
+	if topicFormat != "" {
+		config["topic.format"] = topicFormat
+	}
+	if timestampFormat != "" {
+		config["timestamp.format"] = timestampFormat
+	}
+	return Transform{Name: name, Type: TimestampRouterType, Config: config}
+}
+
+// ExtractField returns a transform replacing the key or value of records with field.
+func ExtractField(name string, target Target, field string) Transform {
+	return Transform{Name: name, Type: "org.apache.kafka.connect.transforms.ExtractField" + string(target), Config: map[string]string{
+		"field": field,
+	}}
+}
+
+// ReplaceFields lists the changes made by a ReplaceField transform.
+type ReplaceFields struct {
+	Exclude types.FlexList
+	Include types.FlexList
+	Renames types.FlexMap
+}
+
+// ReplaceField returns a transform excluding, including and renaming fields of the key or
+// value of records.
+func ReplaceField(name string, target Target, fields ReplaceFields) Transform {
+	config := make(map[string]string)
+	if len(fields.Exclude) > 0 {
+		config["exclude"] = strings.Join(fields.Exclude, ",")
+	}
+	if len(fields.Include) > 0 {
+		config["include"] = strings.Join(fields.Include, ",")
+	}
+	if len(fields.Renames) > 0 {
+		renames := make([]string, 0, len(fields.Renames))
+		for from, to := range fields.Renames {
+			renames = append(renames, from+":"+to)
+		}
+		sort.Strings(renames)
+		config["renames"] = strings.Join(renames, ",")
+	}
+	return Transform{Name: name, Type: "org.apache.kafka.connect.transforms.ReplaceField" + string(target), Config: config}
+}
+
+// With returns a copy of t with the property key set to value.
+func (t Transform) With(key string, value string) Transform {
+	config := make(map[string]string, len(t.Config)+1)
+	for k, v := range t.Config {
+		config[k] = v
+	}
+	config[key] = value
+	t.Config = config
+	return t
+}
+
+// If returns a copy of t applied to the records matching predicate.
+func (t Transform) If(predicate string) Transform {
+	t.Predicate, t.Negate = predicate, false
+	return t
+}
+
+// Unless returns a copy of t applied to the records not matching predicate.
+func (t Transform) Unless(predicate string) Transform {
+	t.Predicate, t.Negate = predicate, true
+	return t
+}
+
+// TopicNameMatches returns a predicate matching records whose topic matches pattern.
+func TopicNameMatches(name string, pattern string) Predicate {
+	return Predicate{Name: name, Type: TopicNameMatchesType, Config: map[string]string{"pattern": pattern}}
+}
+
+// HasHeaderKey returns a predicate matching records having a header called header.
+func HasHeaderKey(name string, header string) Predicate {
+	return Predicate{Name: name, Type: HasHeaderKeyType, Config: map[string]string{"name": header}}
+}
+
+// RecordIsTombstone returns a predicate matching records with a null value.
+func RecordIsTombstone(name string) Predicate {
+	return Predicate{Name: name, Type: RecordIsTombstoneType}
+}
+
+// NewChain returns a chain applying transforms in order.
+func NewChain(transforms ...Transform) Chain {
+	return Chain{}.Add(transforms...)
+}
+
+// Add returns a copy of c with transforms appended.
+func (c Chain) Add(transforms ...Transform) Chain {
+	c.Transforms = append(append([]Transform(nil), c.Transforms...), transforms...)
+	return c
+}
+
+// Define returns a copy of c with predicates appended.
+func (c Chain) Define(predicates ...Predicate) Chain {
+	c.Predicates = append(append([]Predicate(nil), c.Predicates...), predicates...)
+	return c
+}
+
+// Validate checks that names are unique, that transforms use defined predicates and that
+// known transforms and predicates have their required properties.
+func (c Chain) Validate() error {
+	var problems []string
+	predicates := make(map[string]bool)
+	for _, p := range c.Predicates {
+		problems = append(problems, checkPlugin("predicate", p.Name, p.Type, p.Config, predicates, knownPredicates)...)
+	}
+	transforms := make(map[string]bool)
+	for _, t := range c.Transforms {
+		problems = append(problems, checkPlugin("transform", t.Name, t.Type, t.Config, transforms, knownTransforms)...)
+		switch {
+		case t.Predicate != "" && !predicates[t.Predicate]:
+			problems = append(problems, fmt.Sprintf("transform '%s': unknown predicate '%s'", t.Name, t.Predicate))
+		case t.Predicate == "" && t.Negate:
+			problems = append(problems, fmt.Sprintf("transform '%s': negate requires a predicate", t.Name))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("Invalid transform chain: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func checkPlugin(kind string, name string, class string, config map[string]string, seen map[string]bool, known map[string]spec) []string {
+	var problems []string
+	switch {
+	case name == "" || strings.ContainsAny(name, ", \t"):
+		return append(problems, fmt.Sprintf("invalid %s name %q", kind, name))
+	case seen[name]:
+		return append(problems, fmt.Sprintf("duplicate %s '%s'", kind, name))
+	}
+	seen[name] = true
+
+	if class == "" {
+		return append(problems, fmt.Sprintf("%s '%s': missing type", kind, name))
+	}
+	s, ok := known[class]
+	if !ok {
+		return problems
+	}
+	for _, key := range s.required {
+		if config[key] == "" {
+			problems = append(problems, fmt.Sprintf("%s '%s': missing required property '%s'", kind, name, key))
+		}
+	}
+	if len(s.oneOf) > 0 {
+		found := false
+		for _, key := range s.oneOf {
+			found = found || config[key] != ""
+		}
+		if !found {
+			problems = append(problems, fmt.Sprintf("%s '%s': one of '%s' is required", kind, name, strings.Join(s.oneOf, "', '")))
+		}
+	}
+	if s.check != nil {
+		if msg := s.check(config); msg != "" {
+			problems = append(problems, fmt.Sprintf("%s '%s': %s", kind, name, msg))
+		}
+	}
+	return problems
+}
+
+// Render validates c and returns its config keys. An empty chain renders no keys.
+func (c Chain) Render() (map[string]string, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	config := make(map[string]string)
+	if len(c.Transforms) > 0 {
+		names := make([]string, len(c.Transforms))
+		for i, t := range c.Transforms {
+			names[i] = t.Name
+			prefix := "transforms." + t.Name + "."
+			for k, v := range t.Config {
+				config[prefix+k] = v
+			}
+			config[prefix+"type"] = t.Type
+			if t.Predicate != "" {
+				config[prefix+"predicate"] = t.Predicate
+			}
+			if t.Negate {
+				config[prefix+"negate"] = "true"
+			}
+		}
+		config["transforms"] = strings.Join(names, ",")
+	}
+	if len(c.Predicates) > 0 {
+		names := make([]string, len(c.Predicates))
+		for i, p := range c.Predicates {
+			names[i] = p.Name
+			prefix := "predicates." + p.Name + "."
+			for k, v := range p.Config {
+				config[prefix+k] = v
+			}
+			config[prefix+"type"] = p.Type
+		}
+		config["predicates"] = strings.Join(names, ",")
+	}
+	return config, nil
+}
+
+// ParseChain reads the chain of transforms and predicates of config. Keys of transforms and
+// predicates that are not listed in transforms and predicates are ignored, as Kafka Connect
+// does. The chain is not validated.
+func ParseChain(config map[string]string) (Chain, error) {
+	var chain Chain
+	var names types.FlexList
+	_ = names.UnmarshalText([]byte(config["transforms"]))
+	for _, name := range names {
+		props := properties(config, "transforms."+name+".")
+		t := Transform{Name: name, Type: props["type"], Predicate: props["predicate"], Config: props}
+		if negate, ok := props["negate"]; ok {
+			var b types.FlexBool
+			if err := b.UnmarshalText([]byte(negate)); err != nil {
+				return Chain{}, fmt.Errorf("Invalid value for 'transforms.%s.negate': %s", name, err.Error())
+			}
+			t.Negate = bool(b)
+		}
+		delete(props, "type")
+		delete(props, "predicate")
+		delete(props, "negate")
+		chain.Transforms = append(chain.Transforms, t)
+	}
+
+	names = nil
+	_ = names.UnmarshalText([]byte(config["predicates"]))
+	for _, name := range names {
+		props := properties(config, "predicates."+name+".")
+		p := Predicate{Name: name, Type: props["type"], Config: props}
+		delete(props, "type")
+		chain.Predicates = append(chain.Predicates, p)
+	}
+	return chain, nil
+}
+
+// properties returns the keys of config starting with prefix, without it.
+func properties(config map[string]string, prefix string) map[string]string {
+	props := make(map[string]string)
+	for k, v := range config {
+		if strings.HasPrefix(k, prefix) {
+			props[strings.TrimPrefix(k, prefix)] = v
+		}
+	}
+	return props
+}
+
+// isChainKey reports whether key configures transforms or predicates.
+func isChainKey(key string) bool {
+	return key == "transforms" || key == "predicates" ||
+		strings.HasPrefix(key, "transforms.") || strings.HasPrefix(key, "predicates.")
+}
+
+// Transforms returns the chain of transforms and predicates held in Extra.
+func (c *Common) Transforms() (Chain, error) {
+	return ParseChain(c.Extra)
+}
+
+// SetTransforms replaces the transforms and predicates held in Extra with chain.
+func (c *Common) SetTransforms(chain Chain) error {
+	config, err := chain.Render()
+	if err != nil {
+		return err
+	}
+	extra := make(map[string]string, len(c.Extra)+len(config))
+	for k, v := range c.Extra {
+		if !isChainKey(k) {
+			extra[k] = v
+		}
+	}
+	for k, v := range config {
+		extra[k] = v
+	}
+	c.Extra = extra
+	return nil
+}
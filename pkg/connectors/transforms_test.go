@@ -0,0 +1,114 @@
+package connectors_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/walmartdigital/go-kaya/pkg/connectors"
+	"github.com/walmartdigital/go-kaya/pkg/utils/types"
+)
+
+var _ = Describe("Transform chains", func() {
+	chain := connectors.NewChain(
+		connectors.ReplaceField("drop", connectors.Value, connectors.ReplaceFields{
+			Exclude: types.FlexList{"password"},
+			Renames: types.FlexMap{"ts": "timestamp", "msg": "message"},
+		}).Unless("isTombstone"),
+		connectors.ExtractField("key", connectors.Key, "id"),
+		connectors.RegexRouter("route", `(.*)\.logs`, "$1").If("isLogs"),
+		connectors.TimestampRouter("daily", "${topic}-${timestamp}", "yyyy.MM.dd"),
+	).Define(
+		connectors.RecordIsTombstone("isTombstone"),
+		connectors.TopicNameMatches("isLogs", `.*\.logs`),
+	)
+
+	rendered := map[string]string{
+		"transforms":                        "drop,key,route,daily",
+		"transforms.drop.type":              "org.apache.kafka.connect.transforms.ReplaceField$Value",
+		"transforms.drop.exclude":           "password",
+		"transforms.drop.renames":           "msg:message,ts:timestamp",
+		"transforms.drop.predicate":         "isTombstone",
+		"transforms.drop.negate":            "true",
+		"transforms.key.type":               "org.apache.kafka.connect.transforms.ExtractField$Key",
+		"transforms.key.field":              "id",
+		"transforms.route.type":             "org.apache.kafka.connect.transforms.RegexRouter",
+		"transforms.route.regex":            `(.*)\.logs`,
+		"transforms.route.replacement":      "$1",
+		"transforms.route.predicate":        "isLogs",
+		"transforms.daily.type":             "org.apache.kafka.connect.transforms.TimestampRouter",
+		"transforms.daily.topic.format":     "${topic}-${timestamp}",
+		"transforms.daily.timestamp.format": "yyyy.MM.dd",
+		"predicates":                        "isTombstone,isLogs",
+		"predicates.isTombstone.type":       "org.apache.kafka.connect.transforms.predicates.RecordIsTombstone",
+		"predicates.isLogs.type":            "org.apache.kafka.connect.transforms.predicates.TopicNameMatches",
+		"predicates.isLogs.pattern":         `.*\.logs`,
+	}
+
+	It("should render chains into flat configs", func() {
+		config, err := chain.Render()
+		Expect(err).To(BeNil())
+		Expect(config).To(Equal(rendered))
+	})
+
+	It("should parse chains from flat configs", func() {
+		config := map[string]string{"transforms.unused.type": "com.example.Unused"}
+		for k, v := range rendered {
+			config[k] = v
+		}
+		parsed, err := connectors.ParseChain(config)
+		Expect(err).To(BeNil())
+		Expect(parsed.Transforms).To(HaveLen(4))
+		Expect(parsed.Transforms[0].Negate).To(BeTrue())
+
+		again, err := parsed.Render()
+		Expect(err).To(BeNil())
+		Expect(again).To(Equal(rendered))
+
+		_, err = connectors.ParseChain(map[string]string{"transforms": "drop", "transforms.drop.negate": "maybe"})
+		Expect(err).To(MatchError("Invalid value for 'transforms.drop.negate': must be true or false"))
+	})
+
+	It("should validate required properties and references", func() {
+		_, err := connectors.NewChain(
+			connectors.RegexRouter("route", "", "$1"),
+			connectors.ExtractField("route", connectors.Value, "id"),
+			connectors.ReplaceField("drop", connectors.Value, connectors.ReplaceFields{}).If("isLogs"),
+			connectors.Transform{Name: "custom"}.Unless(""),
+		).Define(connectors.HasHeaderKey("hasTrace", "")).Render()
+
+		Expect(err).To(MatchError("Invalid transform chain: " +
+			"predicate 'hasTrace': missing required property 'name'; " +
+			"transform 'route': missing required property 'regex'; " +
+			"duplicate transform 'route'; " +
+			"transform 'drop': one of 'exclude', 'include', 'renames', 'blacklist', 'whitelist' is required; " +
+			"transform 'drop': unknown predicate 'isLogs'; " +
+			"transform 'custom': missing type; " +
+			"transform 'custom': negate requires a predicate"))
+
+		Expect(connectors.NewChain(connectors.Transform{Name: "custom", Type: "com.example.Custom"}).Validate()).To(Succeed())
+	})
+
+	It("should replace the chain of typed configs", func() {
+		var es connectors.ElasticsearchSink
+		Expect(connectors.Unmarshal(map[string]string{
+			"topics":                  "_ims.logs",
+			"transforms":              "old",
+			"transforms.old.type":     "com.example.Old",
+			"behavior.on.null.values": "ignore",
+			"write.timeout":           "5000",
+		}, &es)).To(Succeed())
+
+		old, err := es.Transforms()
+		Expect(err).To(BeNil())
+		Expect(old.Transforms[0].Type).To(Equal("com.example.Old"))
+
+		Expect(es.SetTransforms(chain)).To(Succeed())
+		config, err := connectors.Marshal(&es)
+		Expect(err).To(BeNil())
+		Expect(config).NotTo(HaveKey("transforms.old.type"))
+		Expect(config).To(HaveKeyWithValue("transforms", "drop,key,route,daily"))
+		Expect(config).To(HaveKeyWithValue("write.timeout", "5000"))
+
+		Expect(es.SetTransforms(connectors.Chain{})).To(Succeed())
+		Expect(es.Extra).To(Equal(map[string]string{"write.timeout": "5000"}))
+	})
+})
@@ -4,20 +4,19 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 
-	"github.com/asaskevich/govalidator"
+	"github.com/go-logr/logr"
 	"github.com/walmartdigital/go-kaya/pkg/client"
-
-	// TODO: create an interface for this logging library
-	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"github.com/walmartdigital/go-kaya/pkg/utils/logging"
+	"github.com/walmartdigital/go-kaya/pkg/validator"
 )
 
-var log = logf.Log.WithName("kafka-connect")
-
 // ConnectorStatus ...
 type ConnectorStatus struct {
 	State    string `json:"state"`
 	WorkerID string `json:"worker_id"`
+	Trace    string `json:"trace,omitempty"`
 }
 
 // Status ...
@@ -79,6 +78,9 @@ type Task struct {
 type Connector struct {
 	Name   string            `json:"name"`
 	Config map[string]string `json:"config"`
+	// InitialState is the state Create starts the connector in: StateRunning, StatePaused or
+	// StateStopped. It requires Kafka Connect 3.7 and defaults to running.
+	InitialState string `json:"initial_state,omitempty"`
 }
 
 // Response ...
@@ -96,14 +98,21 @@ type Error struct {
 // Client ...
 type Client struct {
 	httpClient client.HTTPClient
+	log        logr.Logger
+	secrets    *SecretResolver
+	redact     *Redactor
+	names      *validator.NamePolicy
+	validation ValidationMode
+	checker    *validator.Validator
 }
 
 // NewHTTPClient ...
+//
+// Deprecated: use New, which builds the HTTP client from options.
 func NewHTTPClient(url string, config client.HTTPClientConfig, factory client.HTTPClientFactory) (*client.HTTPClient, error) {
 	c, err := initHTTPClient(url, config, factory)
 
 	if err != nil {
-		log.Error(err, "Error creating HTTP client")
 		return nil, err
 	}
 
@@ -124,20 +133,131 @@ func initHTTPClient(url string, config client.HTTPClientConfig, factory client.H
 	return client, nil
 }
 
+// New creates a Kafka Connect client. baseURL is either a bare <host:port>, in which
+// case the scheme is taken from WithScheme, or a full URL such as https://connect:8083.
+// Requests are sent as JSON through a net/http based client unless WithHTTPClientFactory
+// is given. Configs are validated locally before being sent, see WithValidation.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	o := options{
+		httpConfig: client.HTTPClientConfig{
+			Headers: map[string]string{
+				"Content-Type": "application/json",
+				"Accept":       "application/json",
+			},
+		},
+		factory:    client.SimpleHTTPClientFactory{},
+		validation: ValidationLocal,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return newClient(baseURL, o)
+}
+
 // NewClient ...
-func NewClient(kcHost string, config client.HTTPClientConfig, hcf client.HTTPClientFactory) (*Client, error) {
+//
+// Unlike New, configs are not validated before being sent unless WithValidation is given.
+func NewClient(kcHost string, config client.HTTPClientConfig, hcf client.HTTPClientFactory, opts ...Option) (*Client, error) {
+	o := options{
+		httpConfig: config,
+		factory:    hcf,
+		validation: ValidationOff,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return newClient(kcHost, o)
+}
+
+func newClient(baseURL string, o options) (*Client, error) {
 	k := new(Client)
-	h, err := hcf.Create("http://"+kcHost, config)
+	k.log = logging.OrNull(o.logger).WithName("kafka-connect")
+
+	if baseURL == "" {
+		return nil, errors.New("Kafka Connect base URL not provided")
+	}
+
+	if o.factory == nil {
+		o.factory = client.SimpleHTTPClientFactory{}
+	}
+
+	if len(o.failover) > 0 {
+		urls := make([]string, 0, len(o.failover))
+		for _, u := range o.failover {
+			urls = append(urls, o.normalizeURL(u))
+		}
+		o.factory = client.FailoverFactory{Factory: o.factory, URLs: urls}
+	}
+
+	config := o.httpConfig
+	if o.logger != nil && config.Logger == nil {
+		config.Logger = o.logger.WithName("http")
+	}
+
+	h, err := o.factory.Create(o.normalizeURL(baseURL), config)
 
 	if err != nil {
-		log.Error(err, "Error creating Kafka Connect client")
+		k.log.Error(err, "Error creating Kafka Connect client")
 		return nil, err
 	}
 
 	k.httpClient = h
+	k.secrets = o.secrets
+	k.redact = o.redactor
+	k.names = o.names
+	k.validation = o.validation
+	k.checker = o.validator
+	if k.checker == nil {
+		k.checker = validator.New(
+			validator.WithNamePolicy(k.namePolicy()),
+			validator.WithSensitiveKeys(k.redactor().IsSensitive, RedactedValue),
+		)
+	}
 	return k, nil
 }
 
+// normalizeURL adds the scheme to a bare <host:port> and removes any trailing slash.
+func (o options) normalizeURL(url string) string {
+	if !strings.Contains(url, "://") {
+		scheme := o.scheme
+		if scheme == "" {
+			scheme = "http"
+			if o.httpConfig.TLSConfig != nil {
+				scheme = "https"
+			}
+		}
+		url = scheme + "://" + url
+	}
+	return strings.TrimSuffix(url, "/")
+}
+
+func (kcc Client) namePolicy() validator.NamePolicy {
+	if kcc.names == nil {
+		return validator.DefaultNamePolicy
+	}
+	return *kcc.names
+}
+
+func (kcc Client) redactor() *Redactor {
+	if kcc.redact == nil {
+		return DefaultRedactor
+	}
+	return kcc.redact
+}
+
+func (kcc Client) logger() logr.Logger {
+	return logging.OrNull(kcc.log)
+}
+
+// logResult records the outcome of a single Kafka Connect REST call.
+func (kcc Client) logResult(operation string, connector string, status int, err error) {
+	if err != nil {
+		kcc.logger().Error(err, "Kafka Connect request failed", "operation", operation, "connector", connector)
+		return
+	}
+	kcc.logger().V(1).Info("Kafka Connect request completed", "operation", operation, "connector", connector, "status", status)
+}
+
 // HandleNonOKResponse manages any non HTTP 200 response code and conveys the corresponding
 // error to the caller. Returns a response type 'unspecified' if the response code is
 // specifically handled.
@@ -171,13 +291,23 @@ func HandleNonOKResponse(status int, body *[]byte) (*Response, error) {
 
 // Create ...
 func (kcc Client) Create(connector Connector) (*Response, error) {
-	if govalidator.IsDNSName(connector.Name) {
-		configBytes, err := json.Marshal(connector)
+	if kcc.namePolicy().Valid(connector.Name) {
+		if response, err := kcc.check(connector); err != nil {
+			return response, err
+		}
+
+		resolved, secretKeys, err := kcc.resolveSecrets(connector)
+		if err != nil {
+			return &Response{Result: "error"}, err
+		}
+
+		configBytes, err := json.Marshal(resolved)
 		if err != nil {
 			return &Response{Result: "error"}, errors.New("Failed to serialize connector configuration")
 		}
 
 		status, body, err := kcc.httpClient.Post("/connectors", configBytes)
+		kcc.logResult("Create", connector.Name, status, err)
 
 		if err != nil {
 			return &Response{Result: "error"}, fmt.Errorf("Error executing Create on Kafka Connect: %s", err.Error())
@@ -188,6 +318,7 @@ func (kcc Client) Create(connector Connector) (*Response, error) {
 			var createdConnector Connector
 			err := json.Unmarshal(*body, &createdConnector)
 			if err == nil {
+				restorePlaceholders(createdConnector, connector, secretKeys)
 				response := new(Response)
 				response.Result = "success"
 				response.Payload = createdConnector
@@ -195,18 +326,19 @@ func (kcc Client) Create(connector Connector) (*Response, error) {
 			}
 			return &Response{Result: "error"}, errors.New("Failed to deserialize Kafka Connect response")
 		default:
-			return HandleNonOKResponse(status, body)
+			return kcc.handleWriteResponse(connector.Name, status, body, resolved.Config, secretKeys)
 		}
 	}
-	return nil, errors.New("Malformed connector name")
+	return nil, kcc.namePolicy().Check(connector.Name)
 }
 
 // Read gets a connector configuration from KafkaConnect. The returned payload contains
 // contains the configuration of the connector.
 func (kcc Client) Read(connector string) (*Response, error) {
 	var config map[string]string
-	if govalidator.IsDNSName(connector) {
-		status, body, err := kcc.httpClient.Get("/connectors/" + connector + "/config")
+	if kcc.namePolicy().Valid(connector) {
+		status, body, err := kcc.httpClient.Get("/connectors/" + validator.EscapeName(connector) + "/config")
+		kcc.logResult("Read", connector, status, err)
 
 		if err != nil {
 			return &Response{Result: "error"}, fmt.Errorf("Error executing Read on Kafka Connect: %s", err.Error())
@@ -226,15 +358,78 @@ func (kcc Client) Read(connector string) (*Response, error) {
 			return HandleNonOKResponse(status, body)
 		}
 	}
-	return nil, errors.New("Malformed connector name")
+	return nil, kcc.namePolicy().Check(connector)
+}
+
+// List gets the names of the connectors deployed on Kafka Connect. The returned payload
+// is a []string.
+func (kcc Client) List() (*Response, error) {
+	var names []string
+	status, body, err := kcc.httpClient.Get("/connectors")
+	kcc.logResult("List", "", status, err)
+
+	if err != nil {
+		return &Response{Result: "error"}, fmt.Errorf("Error executing List on Kafka Connect: %s", err.Error())
+	}
+
+	switch status {
+	case 200:
+		err := json.Unmarshal(*body, &names)
+		if err == nil {
+			response := new(Response)
+			response.Result = "success"
+			response.Payload = names
+			return response, nil
+		}
+		return &Response{Result: "error"}, errors.New("Failed to deserialize Kafka Connect response")
+	default:
+		return HandleNonOKResponse(status, body)
+	}
+}
+
+// ListStatus gets the status of every connector deployed on Kafka Connect in a single
+// request. The returned payload is a map[string]Status keyed by connector name.
+func (kcc Client) ListStatus() (*Response, error) {
+	var expanded map[string]struct {
+		Status Status `json:"status"`
+	}
+	status, body, err := kcc.httpClient.Get("/connectors?expand=status")
+	kcc.logResult("ListStatus", "", status, err)
+
+	if err != nil {
+		return &Response{Result: "error"}, fmt.Errorf("Error executing ListStatus on Kafka Connect: %s", err.Error())
+	}
+
+	switch status {
+	case 200:
+		err := json.Unmarshal(*body, &expanded)
+		if err == nil {
+			statuses := make(map[string]Status, len(expanded))
+			for name, e := range expanded {
+				statuses[name] = e.Status
+			}
+			response := new(Response)
+			response.Result = "success"
+			response.Payload = statuses
+			return response, nil
+		}
+		return &Response{Result: "error"}, errors.New("Failed to deserialize Kafka Connect response")
+	default:
+		return HandleNonOKResponse(status, body)
+	}
 }
 
 // Update updates an existing connector's configuration. Due to Kadfka Connect's behavior, which
 // creates a connector if the connector does not exist, the function sends a GET first in order
 // to determine whether the connector already exists or not.
 func (kcc Client) Update(connector Connector) (*Response, error) {
-	if govalidator.IsDNSName(connector.Name) {
-		status, body, err := kcc.httpClient.Get("/connectors/" + connector.Name)
+	if kcc.namePolicy().Valid(connector.Name) {
+		if response, err := kcc.check(connector); err != nil {
+			return response, err
+		}
+
+		status, body, err := kcc.httpClient.Get("/connectors/" + validator.EscapeName(connector.Name))
+		kcc.logResult("UpdateLookup", connector.Name, status, err)
 
 		if err != nil {
 			return &Response{Result: "error"}, fmt.Errorf("Error executing Update on Kafka Connect: %s", err.Error())
@@ -244,13 +439,19 @@ func (kcc Client) Update(connector Connector) (*Response, error) {
 			return HandleNonOKResponse(status, body)
 		}
 
-		configBytes, err := json.Marshal(connector.Config)
+		resolved, secretKeys, err := kcc.resolveSecrets(connector)
+		if err != nil {
+			return &Response{Result: "error"}, err
+		}
+
+		configBytes, err := json.Marshal(resolved.Config)
 
 		if err != nil {
 			return &Response{Result: "error"}, errors.New("Failed to serialize connector configuration")
 		}
 
-		status, body, err = kcc.httpClient.Put("/connectors/"+connector.Name+"/config", configBytes)
+		status, body, err = kcc.httpClient.Put("/connectors/"+validator.EscapeName(connector.Name)+"/config", configBytes)
+		kcc.logResult("Update", connector.Name, status, err)
 
 		if err != nil {
 			return &Response{Result: "error"}, fmt.Errorf("Error executing Update on Kafka Connect: %s", err.Error())
@@ -258,26 +459,73 @@ func (kcc Client) Update(connector Connector) (*Response, error) {
 
 		switch status {
 		case 200:
-			var connector Connector
-			err := json.Unmarshal(*body, &connector)
+			var updatedConnector Connector
+			err := json.Unmarshal(*body, &updatedConnector)
 			if err == nil {
+				restorePlaceholders(updatedConnector, connector, secretKeys)
 				response := new(Response)
 				response.Result = "success"
-				response.Payload = connector
+				response.Payload = updatedConnector
 				return response, nil
 			}
 			return &Response{Result: "error"}, errors.New("Failed to deserialize Kafka Connect response")
 		default:
-			return HandleNonOKResponse(status, body)
+			return kcc.handleWriteResponse(connector.Name, status, body, resolved.Config, secretKeys)
+		}
+	}
+	return nil, kcc.namePolicy().Check(connector.Name)
+}
+
+// Apply creates the connector if it does not exist yet, or updates its configuration
+// otherwise.
+func (kcc Client) Apply(connector Connector) (*Response, error) {
+	response, err := kcc.Update(connector)
+	if response != nil && response.Result == "notfound" {
+		return kcc.Create(connector)
+	}
+	return response, err
+}
+
+// resolveSecrets substitutes config provider references when the client has a
+// SecretResolver. The keys holding resolved secrets are returned along with the copy.
+func (kcc Client) resolveSecrets(connector Connector) (Connector, []string, error) {
+	if kcc.secrets == nil {
+		return connector, nil, nil
+	}
+	return kcc.secrets.Resolve(connector)
+}
+
+// secretRedactor returns the redactor of the client extended to the keys holding resolved
+// secrets, whose values must be hidden whatever the names of the keys.
+func (kcc Client) secretRedactor(secretKeys []string) *Redactor {
+	if len(secretKeys) == 0 {
+		return kcc.redactor()
+	}
+	patterns := kcc.redactor().Patterns()
+	for _, key := range secretKeys {
+		patterns = append(patterns, patternEscaper.Replace(key))
+	}
+	return NewRedactor(patterns...)
+}
+
+// patternEscaper quotes the path.Match meta characters of a config key.
+var patternEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`)
+
+// restorePlaceholders puts the original references back in place of resolved secrets
+// echoed by Kafka Connect, so they never reach the caller's logs.
+func restorePlaceholders(target Connector, original Connector, keys []string) {
+	for _, key := range keys {
+		if _, ok := target.Config[key]; ok {
+			target.Config[key] = original.Config[key]
 		}
 	}
-	return nil, errors.New("Malformed connector name")
 }
 
 // Delete ...
 func (kcc Client) Delete(connector string) (*Response, error) {
-	if govalidator.IsDNSName(connector) {
-		status, body, err := kcc.httpClient.Delete("/connectors/" + connector)
+	if kcc.namePolicy().Valid(connector) {
+		status, body, err := kcc.httpClient.Delete("/connectors/" + validator.EscapeName(connector))
+		kcc.logResult("Delete", connector, status, err)
 
 		if err != nil {
 			return &Response{Result: "error"}, fmt.Errorf("Error executing Delete on Kafka Connect: %s", err.Error())
@@ -292,14 +540,15 @@ func (kcc Client) Delete(connector string) (*Response, error) {
 			return HandleNonOKResponse(status, body)
 		}
 	}
-	return nil, errors.New("Malformed connector name")
+	return nil, kcc.namePolicy().Check(connector)
 }
 
 // GetStatus ...
 func (kcc Client) GetStatus(connector string) (*Response, error) {
 	var connectorStatus Status
-	if govalidator.IsDNSName(connector) {
-		status, body, err := kcc.httpClient.Get("/connectors/" + connector + "/status")
+	if kcc.namePolicy().Valid(connector) {
+		status, body, err := kcc.httpClient.Get("/connectors/" + validator.EscapeName(connector) + "/status")
+		kcc.logResult("GetStatus", connector, status, err)
 
 		if err != nil {
 			return &Response{Result: "error"}, fmt.Errorf("Error executing GetStatus on Kafka Connect: %s", err.Error())
@@ -319,14 +568,15 @@ func (kcc Client) GetStatus(connector string) (*Response, error) {
 			return HandleNonOKResponse(status, body)
 		}
 	}
-	return nil, errors.New("Malformed connector name")
+	return nil, kcc.namePolicy().Check(connector)
 }
 
 // RestartTask ...
 func (kcc Client) RestartTask(connector string, taskID int) (*Response, error) {
-	if govalidator.IsDNSName(connector) {
-		endpoint := fmt.Sprintf("/connectors/%s/tasks/%d/restart", connector, taskID)
+	if kcc.namePolicy().Valid(connector) {
+		endpoint := fmt.Sprintf("/connectors/%s/tasks/%d/restart", validator.EscapeName(connector), taskID)
 		status, body, err := kcc.httpClient.Post(endpoint, []byte{})
+		kcc.logResult("RestartTask", connector, status, err)
 
 		if err != nil {
 			return &Response{Result: "error"}, fmt.Errorf("Error executing RestartTask on Kafka Connect: %s", err.Error())
@@ -341,14 +591,15 @@ func (kcc Client) RestartTask(connector string, taskID int) (*Response, error) {
 			return HandleNonOKResponse(status, body)
 		}
 	}
-	return nil, errors.New("Malformed connector name")
+	return nil, kcc.namePolicy().Check(connector)
 }
 
 // RestartConnector ...
 func (kcc Client) RestartConnector(connector string) (*Response, error) {
-	if govalidator.IsDNSName(connector) {
-		endpoint := fmt.Sprintf("/connectors/%s/restart", connector)
+	if kcc.namePolicy().Valid(connector) {
+		endpoint := fmt.Sprintf("/connectors/%s/restart", validator.EscapeName(connector))
 		status, body, err := kcc.httpClient.Post(endpoint, []byte{})
+		kcc.logResult("RestartConnector", connector, status, err)
 
 		if err != nil {
 			return &Response{Result: "error"}, fmt.Errorf("Error executing RestartConnector on Kafka Connect: %s", err.Error())
@@ -363,5 +614,5 @@ func (kcc Client) RestartConnector(connector string) (*Response, error) {
 			return HandleNonOKResponse(status, body)
 		}
 	}
-	return nil, errors.New("Malformed connector name")
+	return nil, kcc.namePolicy().Check(connector)
 }
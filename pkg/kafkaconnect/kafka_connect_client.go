@@ -15,7 +15,53 @@ type KafkaConnectClient interface {
 	RestartConnector(connector string) (*Response, error)
 }
 
+// ConnectorManager adds listing, applying and validating connectors to KafkaConnectClient.
+// It is a separate interface so that existing implementations of KafkaConnectClient keep
+// satisfying it. Client implements it.
+type ConnectorManager interface {
+	KafkaConnectClient
+	List() (*Response, error)
+	ListStatus() (*Response, error)
+	Apply(connector Connector) (*Response, error)
+	Validate(connector Connector) (*Response, error)
+}
+
+// LifecycleManager pauses, resumes and stops connectors and manages their offsets, which
+// newer Kafka Connect versions support, as told by ServerInfo. Client implements it.
+type LifecycleManager interface {
+	ServerInfo() (*Response, error)
+	Pause(connector string) (*Response, error)
+	Resume(connector string) (*Response, error)
+	Stop(connector string) (*Response, error)
+	GetOffsets(connector string) (*Response, error)
+	AlterOffsets(connector string, offsets Offsets) (*Response, error)
+}
+
+var (
+	_ ConnectorManager = (*Client)(nil)
+	_ LifecycleManager = (*Client)(nil)
+)
+
 // KafkaConnectClientFactory ...
 type KafkaConnectClientFactory interface {
 	Create(string, client.HTTPClientFactory) (KafkaConnectClient, error)
 }
+
+// ClientFactory is the default KafkaConnectClientFactory. Every client it creates is
+// configured with Options.
+type ClientFactory struct {
+	Options []Option
+}
+
+// Create builds a Client for baseURL using hcf to create the underlying HTTP client.
+func (f ClientFactory) Create(baseURL string, hcf client.HTTPClientFactory) (KafkaConnectClient, error) {
+	opts := append([]Option{}, f.Options...)
+	if hcf != nil {
+		opts = append(opts, WithHTTPClientFactory(hcf))
+	}
+	c, err := New(baseURL, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
@@ -3,13 +3,16 @@ package kafkaconnect_test
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
+	"github.com/go-logr/logr"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/walmartdigital/go-kaya/pkg/client"
 	"github.com/walmartdigital/go-kaya/pkg/kafkaconnect"
 	"github.com/walmartdigital/go-kaya/pkg/mocks"
+	"github.com/walmartdigital/go-kaya/pkg/validator"
 )
 
 var ctrl *gomock.Controller
@@ -43,6 +46,116 @@ var _ = Describe("New Client", func() {
 	})
 })
 
+var _ = Describe("New Client with options", func() {
+	var (
+		fakeHTTPClient        *mocks.MockHTTPClient
+		fakeHTTPClientFactory *mocks.MockHTTPClientFactory
+	)
+
+	BeforeEach(func() {
+		fakeHTTPClient = mocks.NewMockHTTPClient(ctrl)
+		fakeHTTPClientFactory = mocks.NewMockHTTPClientFactory(ctrl)
+	})
+
+	It("should build the HTTP client from options", func() {
+		fakeHTTPClientFactory.EXPECT().Create("https://somehost:8083", gomock.Any()).DoAndReturn(
+			func(url string, config client.HTTPClientConfig) (client.HTTPClient, error) {
+				Expect(config.AuthType).To(Equal(client.BasicAuth))
+				Expect(config.Creds).To(Equal(map[string]string{"username": "kaya", "password": "secret"}))
+				Expect(config.Headers).To(HaveKeyWithValue("Content-Type", "application/json"))
+				Expect(config.Headers).To(HaveKeyWithValue("User-Agent", "go-kaya-test"))
+				Expect(config.Timeout).To(Equal(5 * time.Second))
+				Expect(config.RetryCount).To(Equal(3))
+				return fakeHTTPClient, nil
+			},
+		).Times(1)
+
+		kcc, err := kafkaconnect.New(
+			"somehost:8083",
+			kafkaconnect.WithHTTPClientFactory(fakeHTTPClientFactory),
+			kafkaconnect.WithScheme("https"),
+			kafkaconnect.WithBasicAuth("kaya", "secret"),
+			kafkaconnect.WithUserAgent("go-kaya-test"),
+			kafkaconnect.WithTimeout(5*time.Second),
+			kafkaconnect.WithRetryPolicy(3, time.Second, 10*time.Second, nil),
+		)
+		Expect(err).To(BeNil())
+		Expect(kcc).NotTo(BeNil())
+	})
+
+	It("should keep the scheme of a full base URL", func() {
+		fakeHTTPClientFactory.EXPECT().Create("https://somehost", gomock.Any()).Return(fakeHTTPClient, nil).Times(1)
+
+		kcc, err := kafkaconnect.New("https://somehost/", kafkaconnect.WithHTTPClientFactory(fakeHTTPClientFactory))
+		Expect(err).To(BeNil())
+		Expect(kcc).NotTo(BeNil())
+	})
+
+	It("should fail without a base URL", func() {
+		kcc, err := kafkaconnect.New("", kafkaconnect.WithHTTPClientFactory(fakeHTTPClientFactory))
+		Expect(err).NotTo(BeNil())
+		Expect(kcc).To(BeNil())
+	})
+
+	It("should create clients through the default factory", func() {
+		fakeHTTPClientFactory.EXPECT().Create("http://somehost", gomock.Any()).Return(fakeHTTPClient, nil).Times(1)
+
+		factory := kafkaconnect.ClientFactory{Options: []kafkaconnect.Option{kafkaconnect.WithTimeout(time.Second)}}
+		kcc, err := factory.Create("somehost", fakeHTTPClientFactory)
+		Expect(err).To(BeNil())
+		Expect(kcc).NotTo(BeNil())
+	})
+})
+
+var _ = Describe("Client logging", func() {
+	var (
+		fakeHTTPClient        *mocks.MockHTTPClient
+		fakeHTTPClientFactory *mocks.MockHTTPClientFactory
+		logger                *recordingLogger
+	)
+
+	BeforeEach(func() {
+		fakeHTTPClient = mocks.NewMockHTTPClient(ctrl)
+		fakeHTTPClientFactory = mocks.NewMockHTTPClientFactory(ctrl)
+		logger = &recordingLogger{}
+	})
+
+	It("should pass the logger to the HTTP client and log operations", func() {
+		fakeHTTPClientFactory.EXPECT().Create("http://somehost", gomock.Any()).DoAndReturn(
+			func(url string, config client.HTTPClientConfig) (client.HTTPClient, error) {
+				Expect(config.Logger).NotTo(BeNil())
+				return fakeHTTPClient, nil
+			},
+		).Times(1)
+		fakeHTTPClient.EXPECT().Delete("/connectors/logging").Return(204, nil, nil).Times(1)
+
+		kcc, err := kafkaconnect.NewClient("somehost", client.HTTPClientConfig{}, fakeHTTPClientFactory, kafkaconnect.WithLogger(logger))
+		Expect(err).To(BeNil())
+
+		_, err = kcc.Delete("logging")
+		Expect(err).To(BeNil())
+		Expect(logger.entries).To(ContainElement([]interface{}{"operation", "Delete", "connector", "logging", "status", 204}))
+	})
+
+	It("should log the lookup of Update under its own operation", func() {
+		fakeHTTPClientFactory.EXPECT().Create("http://somehost", gomock.Any()).Return(fakeHTTPClient, nil).Times(1)
+		existing := []byte(`{"name":"logging","config":{}}`)
+		updated := []byte(`{"name":"logging","config":{"topics":"logs"}}`)
+		fakeHTTPClient.EXPECT().Get("/connectors/logging").Return(200, &existing, nil).Times(1)
+		fakeHTTPClient.EXPECT().Put("/connectors/logging/config", gomock.Any()).Return(200, &updated, nil).Times(1)
+
+		kcc, err := kafkaconnect.NewClient("somehost", client.HTTPClientConfig{}, fakeHTTPClientFactory, kafkaconnect.WithLogger(logger))
+		Expect(err).To(BeNil())
+
+		_, err = kcc.Update(kafkaconnect.Connector{Name: "logging", Config: map[string]string{"topics": "logs"}})
+		Expect(err).To(BeNil())
+		Expect(logger.entries).To(Equal([][]interface{}{
+			{"operation", "UpdateLookup", "connector", "logging", "status", 200},
+			{"operation", "Update", "connector", "logging", "status", 200},
+		}))
+	})
+})
+
 var _ = Describe("Read from Kafka Connect", func() {
 	var (
 		fakeHTTPClient        *mocks.MockHTTPClient
@@ -88,6 +201,39 @@ var _ = Describe("Read from Kafka Connect", func() {
 		Expect(resp.Payload.(map[string]string)).To(Equal(kafkaConnectConfig))
 	})
 
+	It("should list connectors", func() {
+		responseBody := []byte(`["logging","metrics"]`)
+
+		fakeHTTPClient.EXPECT().Get("/connectors").Return(
+			200,
+			&responseBody,
+			nil,
+		).Times(1)
+
+		resp, err := kafkaConnectClient.List()
+		Expect(err).To(BeNil())
+		Expect(resp.Result).To(BeIdenticalTo("success"))
+		Expect(resp.Payload).To(Equal([]string{"logging", "metrics"}))
+	})
+
+	It("should list the status of every connector", func() {
+		responseBody := []byte(`{"logging":{"status":{"name":"logging","connector":{"state":"RUNNING","worker_id":"somenode"},"tasks":[{"id":0,"state":"FAILED","worker_id":"somenode","trace":"boom"}]}}}`)
+
+		fakeHTTPClient.EXPECT().Get("/connectors?expand=status").Return(
+			200,
+			&responseBody,
+			nil,
+		).Times(1)
+
+		resp, err := kafkaConnectClient.ListStatus()
+		Expect(err).To(BeNil())
+		Expect(resp.Result).To(BeIdenticalTo("success"))
+		statuses := resp.Payload.(map[string]kafkaconnect.Status)
+		Expect(statuses).To(HaveKey("logging"))
+		Expect(statuses["logging"].Connector.State).To(Equal("RUNNING"))
+		Expect(statuses["logging"].GetFailedTasks()).To(Equal([]int{0}))
+	})
+
 	It("should get a connector status", func() {
 		task := kafkaconnect.Task{
 			ID:       0,
@@ -282,6 +428,11 @@ var _ = Describe("Read from Kafka Connect", func() {
 	})
 
 	It("should throw an error because connector name is invalid", func() {
+		fakeHTTPClientFactory.EXPECT().Create("http://somehost", client.HTTPClientConfig{}).Return(
+			fakeHTTPClient, nil,
+		).Times(1)
+		kafkaConnectClient, _ = kafkaconnect.NewClient("somehost", client.HTTPClientConfig{}, fakeHTTPClientFactory, kafkaconnect.WithNamePolicy(validator.StrictNamePolicy))
+
 		resp, err2 := kafkaConnectClient.Read("/$%&")
 		Expect(err2).NotTo(BeNil())
 		Expect(resp).To(BeNil())
@@ -548,3 +699,31 @@ var _ = Describe("Delete Kafka Connect connectors", func() {
 		Expect(resp.Result).To(BeIdenticalTo("notfound"))
 	})
 })
+
+type recordingLogger struct {
+	entries [][]interface{}
+}
+
+func (l *recordingLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.entries = append(l.entries, keysAndValues)
+}
+
+func (l *recordingLogger) Enabled() bool {
+	return true
+}
+
+func (l *recordingLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	l.entries = append(l.entries, keysAndValues)
+}
+
+func (l *recordingLogger) V(level int) logr.InfoLogger {
+	return l
+}
+
+func (l *recordingLogger) WithName(name string) logr.Logger {
+	return l
+}
+
+func (l *recordingLogger) WithValues(keysAndValues ...interface{}) logr.Logger {
+	return l
+}
@@ -0,0 +1,258 @@
+package kafkaconnecttest
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"time"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Fault is a scripted misbehaviour of the fake server. A fault matches requests by method
+// and path, and once it fires it may delay the response, change the state of a connector
+// before the request is handled, replace the response with an error or drop the connection.
+//
+// Faults are evaluated in the order they were injected. Every matching fault that fires
+// applies its delay and state changes, the first one that replaces the response wins.
+type Fault struct {
+	// Method matches the HTTP method, empty matches any method.
+	Method string `yaml:"method,omitempty"`
+	// Path is a path.Match pattern such as /connectors/*/status, empty matches any path.
+	Path string `yaml:"path,omitempty"`
+	// After skips the first After matching requests.
+	After int `yaml:"after,omitempty"`
+	// Times limits how often the fault fires, zero means no limit.
+	Times int `yaml:"times,omitempty"`
+	// For limits how long the fault stays active after it is injected, zero means forever.
+	For time.Duration `yaml:"for,omitempty"`
+
+	// Delay responds slowly.
+	Delay time.Duration `yaml:"delay,omitempty"`
+	// Status replaces the response with an error of this HTTP status.
+	Status int `yaml:"status,omitempty"`
+	// Message is the error message sent with Status.
+	Message string `yaml:"message,omitempty"`
+	// Rebalance replaces write requests with the 409 a worker returns while its group is
+	// rebalancing. Read requests are not affected.
+	Rebalance bool `yaml:"rebalance,omitempty"`
+	// Drop sends the response headers and part of the body, then closes the connection.
+	Drop bool `yaml:"drop,omitempty"`
+	// FailTask moves a task to the FAILED state before the request is handled.
+	FailTask *TaskFailure `yaml:"failTask,omitempty"`
+	// FailConnector moves the named connector to the FAILED state before the request is handled.
+	FailConnector string `yaml:"failConnector,omitempty"`
+	// DeleteConnector deletes the named connector before the request is handled, simulating
+	// a concurrent deletion.
+	DeleteConnector string `yaml:"deleteConnector,omitempty"`
+}
+
+// TaskFailure identifies the task a fault fails.
+type TaskFailure struct {
+	Connector string `yaml:"connector"`
+	Task      int    `yaml:"task"`
+	Trace     string `yaml:"trace,omitempty"`
+}
+
+// Scenario is a list of faults, usually loaded from YAML:
+//
+//	faults:
+//	  - method: GET
+//	    path: /connectors/logging/status
+//	    after: 3
+//	    times: 1
+//	    failTask: {connector: logging, task: 2, trace: boom}
+//	  - method: PUT
+//	    times: 1
+//	    status: 500
+//	  - rebalance: true
+//	    for: 5s
+type Scenario struct {
+	Faults []Fault `yaml:"faults"`
+}
+
+// ParseScenario parses a YAML scenario.
+func ParseScenario(content []byte) (Scenario, error) {
+	var scenario Scenario
+	if err := yaml.UnmarshalStrict(content, &scenario); err != nil {
+		return Scenario{}, fmt.Errorf("Failed to parse fault scenario: %s", err.Error())
+	}
+	return scenario, nil
+}
+
+// LoadScenario reads and parses a YAML scenario.
+func LoadScenario(r io.Reader) (Scenario, error) {
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return Scenario{}, err
+	}
+	return ParseScenario(content)
+}
+
+// FailTaskAfter fails a task of a connector once its status has been polled polls times.
+func FailTaskAfter(connector string, task int, polls int) Fault {
+	return Fault{
+		Method:   http.MethodGet,
+		Path:     "/connectors/" + connector + "/status",
+		After:    polls,
+		Times:    1,
+		FailTask: &TaskFailure{Connector: connector, Task: task, Trace: "org.apache.kafka.connect.errors.ConnectException: injected failure"},
+	}
+}
+
+// ErrorOnNext replaces the response to the next request with the given method by an
+// error with the given status.
+func ErrorOnNext(method string, status int) Fault {
+	return Fault{Method: method, Times: 1, Status: status}
+}
+
+// RebalanceFor rejects write requests with HTTP 409 for d.
+func RebalanceFor(d time.Duration) Fault {
+	return Fault{Rebalance: true, For: d}
+}
+
+// DropConnection closes the connection mid-body for every request matching method and
+// pathPattern.
+func DropConnection(method string, pathPattern string) Fault {
+	return Fault{Method: method, Path: pathPattern, Drop: true}
+}
+
+// Slow delays every response by d.
+func Slow(d time.Duration) Fault {
+	return Fault{Delay: d}
+}
+
+type faultState struct {
+	fault    Fault
+	injected time.Time
+	matched  int
+	fired    int
+}
+
+func (f *faultState) matches(r *http.Request, now time.Time) bool {
+	if f.fault.For > 0 && now.Sub(f.injected) > f.fault.For {
+		return false
+	}
+	if f.fault.Times > 0 && f.fired >= f.fault.Times {
+		return false
+	}
+	if f.fault.Rebalance && r.Method == http.MethodGet {
+		return false
+	}
+	if f.fault.Method != "" && f.fault.Method != r.Method {
+		return false
+	}
+	if f.fault.Path != "" {
+		if ok, _ := path.Match(f.fault.Path, r.URL.Path); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Inject adds faults to the server.
+func (s *Server) Inject(faults ...Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for _, f := range faults {
+		s.faults = append(s.faults, &faultState{fault: f, injected: now})
+	}
+}
+
+// Play injects every fault of a scenario.
+func (s *Server) Play(scenario Scenario) {
+	s.Inject(scenario.Faults...)
+}
+
+// ClearFaults removes every injected fault.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Fired returns how many times each injected fault fired, in injection order.
+func (s *Server) Fired() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fired := make([]int, 0, len(s.faults))
+	for _, f := range s.faults {
+		fired = append(fired, f.fired)
+	}
+	return fired
+}
+
+// fire evaluates the injected faults for a request. State changes are applied right away,
+// the returned fault carries the accumulated delay and the response replacement, if any.
+// Must be called with s.mu held.
+func (s *Server) fire(r *http.Request) Fault {
+	var result Fault
+	now := time.Now()
+
+	for _, f := range s.faults {
+		if !f.matches(r, now) {
+			continue
+		}
+		f.matched++
+		if f.matched <= f.fault.After {
+			continue
+		}
+		f.fired++
+
+		result.Delay += f.fault.Delay
+		s.applyFault(f.fault)
+
+		if result.Status == 0 && !result.Drop {
+			switch {
+			case f.fault.Drop:
+				result.Drop = true
+			case f.fault.Rebalance:
+				result.Status = http.StatusConflict
+				result.Message = rebalanceMessage
+			case f.fault.Status != 0:
+				result.Status = f.fault.Status
+				result.Message = f.fault.Message
+				if result.Message == "" {
+					result.Message = http.StatusText(f.fault.Status)
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+func (s *Server) applyFault(f Fault) {
+	if f.FailTask != nil {
+		if c, ok := s.connectors[f.FailTask.Connector]; ok && f.FailTask.Task >= 0 && f.FailTask.Task < len(c.tasks) {
+			c.tasks[f.FailTask.Task].State = StateFailed
+			c.tasks[f.FailTask.Task].Trace = f.FailTask.Trace
+		}
+	}
+	if c, ok := s.connectors[f.FailConnector]; ok {
+		c.state = StateFailed
+		c.trace = "org.apache.kafka.connect.errors.ConnectException: injected failure"
+	}
+	if f.DeleteConnector != "" {
+		delete(s.connectors, f.DeleteConnector)
+	}
+}
+
+// dropConnection writes the response headers and a truncated body, then closes the
+// underlying connection so the client sees an unexpected EOF.
+func dropConnection(w http.ResponseWriter) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		panic(http.ErrAbortHandler)
+	}
+	conn, buf, err := hijacker.Hijack()
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+	_, _ = buf.WriteString("HTTP/1.1 200 OK\r\nContent-Type: application/json\r\nContent-Length: 1024\r\n\r\n{\"name\":")
+	_ = buf.Flush()
+	_ = conn.Close()
+}
@@ -0,0 +1,166 @@
+package kafkaconnecttest_test
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/walmartdigital/go-kaya/pkg/client"
+	"github.com/walmartdigital/go-kaya/pkg/kafkaconnect"
+	"github.com/walmartdigital/go-kaya/pkg/kafkaconnect/kafkaconnecttest"
+)
+
+var _ = Describe("Fault injection", func() {
+	var (
+		server    *kafkaconnecttest.Server
+		kcc       *kafkaconnect.Client
+		connector kafkaconnect.Connector
+	)
+
+	BeforeEach(func() {
+		var err error
+		server = kafkaconnecttest.NewServer()
+		kcc, err = kafkaconnect.New(server.URL)
+		Expect(err).To(BeNil())
+
+		connector = kafkaconnect.Connector{
+			Name: "logging",
+			Config: map[string]string{
+				"connector.class": "io.confluent.connect.elasticsearch.ElasticsearchSinkConnector",
+				"tasks.max":       "3",
+				"topics":          "_dumblogger.logs",
+				"connection.url":  "http://elasticsearch:9200",
+			},
+		}
+		server.AddConnector(connector)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should fail a task after a number of status polls", func() {
+		server.Inject(kafkaconnecttest.FailTaskAfter("logging", 2, 3))
+
+		for i := 0; i < 3; i++ {
+			resp, err := kcc.GetStatus("logging")
+			Expect(err).To(BeNil())
+			Expect(resp.Payload.(kafkaconnect.Status).GetFailedTasks()).To(BeEmpty())
+		}
+
+		resp, err := kcc.GetStatus("logging")
+		Expect(err).To(BeNil())
+		Expect(resp.Payload.(kafkaconnect.Status).GetFailedTasks()).To(Equal([]int{2}))
+		Expect(server.Fired()).To(Equal([]int{1}))
+	})
+
+	It("should return an error on the next PUT only", func() {
+		server.Inject(kafkaconnecttest.ErrorOnNext(http.MethodPut, 500))
+
+		resp, err := kcc.Update(connector)
+		Expect(err).NotTo(BeNil())
+		Expect(resp.Result).To(Equal("unspecified"))
+
+		resp, err = kcc.Update(connector)
+		Expect(err).To(BeNil())
+		Expect(resp.Result).To(Equal("success"))
+	})
+
+	It("should let the client retry policy recover from transient errors", func() {
+		server.Inject(kafkaconnecttest.ErrorOnNext(http.MethodPut, 500))
+
+		retrying, err := kafkaconnect.New(server.URL, kafkaconnect.WithRetryPolicy(2, time.Millisecond, 10*time.Millisecond,
+			func(resp *client.Response, err error) bool {
+				return resp != nil && resp.StatusCode >= 500
+			},
+		))
+		Expect(err).To(BeNil())
+
+		resp, err := retrying.Update(connector)
+		Expect(err).To(BeNil())
+		Expect(resp.Result).To(Equal("success"))
+	})
+
+	It("should reject writes while rebalancing for a while", func() {
+		server.Inject(kafkaconnecttest.RebalanceFor(100 * time.Millisecond))
+
+		resp, err := kcc.GetStatus("logging")
+		Expect(err).To(BeNil())
+		Expect(resp.Result).To(Equal("success"))
+
+		resp, err = kcc.RestartConnector("logging")
+		Expect(err).NotTo(BeNil())
+		Expect(resp.Result).To(Equal("conflict"))
+
+		time.Sleep(150 * time.Millisecond)
+
+		resp, err = kcc.RestartConnector("logging")
+		Expect(err).To(BeNil())
+		Expect(resp.Result).To(Equal("success"))
+	})
+
+	It("should drop the connection mid-body", func() {
+		server.Inject(kafkaconnecttest.DropConnection(http.MethodGet, "/connectors/*/config"))
+
+		resp, err := kcc.Read("logging")
+		Expect(err).NotTo(BeNil())
+		Expect(resp.Result).To(Equal("error"))
+	})
+
+	It("should respond slowly", func() {
+		server.Inject(kafkaconnecttest.Slow(100 * time.Millisecond))
+
+		start := time.Now()
+		_, err := kcc.Read("logging")
+		Expect(err).To(BeNil())
+		Expect(time.Since(start)).To(BeNumerically(">=", 100*time.Millisecond))
+	})
+
+	It("should expose the Update GET-then-PUT race", func() {
+		server.Inject(kafkaconnecttest.Fault{Method: http.MethodPut, Times: 1, DeleteConnector: "logging"})
+
+		resp, err := kcc.Update(connector)
+		Expect(err).NotTo(BeNil())
+		Expect(resp.Result).To(Equal("unspecified"))
+		Expect(server.Connectors()).To(Equal([]string{"logging"}))
+	})
+
+	It("should play scenarios loaded from YAML", func() {
+		scenario, err := kafkaconnecttest.LoadScenario(strings.NewReader(`
+faults:
+  - method: GET
+    path: /connectors/logging/status
+    after: 1
+    times: 1
+    failTask: {connector: logging, task: 0, trace: boom}
+  - method: POST
+    path: /connectors/*/restart
+    status: 500
+    message: worker unavailable
+    times: 1
+  - delay: 10ms
+`))
+		Expect(err).To(BeNil())
+		Expect(scenario.Faults).To(HaveLen(3))
+		Expect(scenario.Faults[2].Delay).To(Equal(10 * time.Millisecond))
+
+		server.Play(scenario)
+
+		_, _ = kcc.GetStatus("logging")
+		resp, err := kcc.GetStatus("logging")
+		Expect(err).To(BeNil())
+		Expect(resp.Payload.(kafkaconnect.Status).Tasks[0].Trace).To(Equal("boom"))
+
+		resp, err = kcc.RestartConnector("logging")
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("worker unavailable"))
+		Expect(server.Fired()).To(Equal([]int{1, 1, 3}))
+	})
+
+	It("should reject scenarios with unknown fields", func() {
+		_, err := kafkaconnecttest.ParseScenario([]byte("faults:\n  - explode: true\n"))
+		Expect(err).NotTo(BeNil())
+	})
+})
@@ -0,0 +1,801 @@
+// Package kafkaconnecttest provides an in-process fake Kafka Connect worker for tests.
+//
+// The fake keeps connectors in memory, derives their tasks from tasks.max and implements
+// the Kafka Connect REST API closely enough for kafkaconnect.Client and other HTTP clients
+// to be tested against real HTTP round trips:
+//
+//	s := kafkaconnecttest.NewServer()
+//	defer s.Close()
+//
+//	kcc, _ := kafkaconnect.New(s.URL)
+//	kcc.Create(connector)
+//	s.FailTask(connector.Name, 0, "java.lang.NullPointerException")
+package kafkaconnecttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/walmartdigital/go-kaya/pkg/kafkaconnect"
+)
+
+// Connector and task states reported by the fake worker.
+const (
+	StateRunning    = "RUNNING"
+	StatePaused     = "PAUSED"
+	StateStopped    = "STOPPED"
+	StateFailed     = "FAILED"
+	StateUnassigned = "UNASSIGNED"
+)
+
+// DefaultWorkerID is the worker every connector and task is assigned to.
+const DefaultWorkerID = "kafkaconnecttest:8083"
+
+// Version is reported by the root endpoint of a new Server, see SetVersion.
+const Version = "3.7.0"
+
+const rebalanceMessage = "Cannot complete request momentarily due to stale configuration (typically caused by a concurrent config change)"
+
+// Plugin describes a connector plugin installed on the fake worker.
+type Plugin struct {
+	Class   string `json:"class"`
+	Type    string `json:"type"`
+	Version string `json:"version"`
+}
+
+// DefaultPlugins are the connector plugins installed on a new Server.
+var DefaultPlugins = []Plugin{
+	{Class: "io.confluent.connect.elasticsearch.ElasticsearchSinkConnector", Type: "sink", Version: "5.5.1"},
+	{Class: "io.confluent.connect.jdbc.JdbcSinkConnector", Type: "sink", Version: "5.5.1"},
+	{Class: "io.confluent.connect.jdbc.JdbcSourceConnector", Type: "source", Version: "5.5.1"},
+	{Class: "io.confluent.connect.s3.S3SinkConnector", Type: "sink", Version: "5.5.1"},
+	{Class: "io.debezium.connector.mysql.MySqlConnector", Type: "source", Version: "1.2.1.Final"},
+	{Class: "org.apache.kafka.connect.file.FileStreamSinkConnector", Type: "sink", Version: Version},
+	{Class: "org.apache.kafka.connect.file.FileStreamSourceConnector", Type: "source", Version: Version},
+}
+
+// Offset is a single source partition offset, or a consumer group offset for sinks.
+type Offset struct {
+	Partition map[string]interface{} `json:"partition"`
+	Offset    map[string]interface{} `json:"offset"`
+}
+
+type connector struct {
+	name    string
+	config  map[string]string
+	state   string
+	trace   string
+	tasks   []kafkaconnect.Task
+	offsets []Offset
+}
+
+// Server is a fake Kafka Connect worker listening on a local loopback address.
+type Server struct {
+	// URL is the base URL of the form http://ipaddr:port with no trailing slash.
+	URL string
+
+	server      *httptest.Server
+	mu          sync.Mutex
+	connectors  map[string]*connector
+	plugins     []Plugin
+	workerID    string
+	latency     time.Duration
+	rebalancing bool
+	noOffsets   bool
+	version     string
+	requests    []string
+	faults      []*faultState
+}
+
+// NewServer starts and returns a new fake Kafka Connect worker. The caller should call
+// Close when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		connectors: map[string]*connector{},
+		plugins:    append([]Plugin{}, DefaultPlugins...),
+		workerID:   DefaultWorkerID,
+		version:    Version,
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.server.URL
+	return s
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Host returns the <host:port> the server listens on, as expected by kafkaconnect.NewClient.
+func (s *Server) Host() string {
+	return strings.TrimPrefix(s.URL, "http://")
+}
+
+// SetLatency delays every response by d.
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = d
+}
+
+// SetRebalancing makes every write request fail with HTTP 409 until it is called with false,
+// like a worker whose group is rebalancing.
+func (s *Server) SetRebalancing(rebalancing bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rebalancing = rebalancing
+}
+
+// SetPlugins replaces the installed connector plugins.
+func (s *Server) SetPlugins(plugins []Plugin) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.plugins = append([]Plugin{}, plugins...)
+}
+
+// SetOffsetsSupported makes the offsets endpoints answer 404 when supported is false, as
+// workers older than Kafka 3.5 do.
+func (s *Server) SetOffsetsSupported(supported bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.noOffsets = !supported
+}
+
+// SetVersion changes the Kafka version reported by the root endpoint. Like a real worker,
+// the server then answers 404 on the stop and offsets endpoints below 3.5 and rejects the
+// initial_state of a new connector below 3.7.
+func (s *Server) SetVersion(version string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.version = version
+}
+
+// AddConnector creates or replaces a running connector without going through HTTP.
+func (s *Server) AddConnector(c kafkaconnect.Connector) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.putConnector(c.Name, c.Config)
+}
+
+// Connector returns the stored configuration of a connector.
+func (s *Server) Connector(name string) (kafkaconnect.Connector, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.connectors[name]
+	if !ok {
+		return kafkaconnect.Connector{}, false
+	}
+	return kafkaconnect.Connector{Name: c.name, Config: copyConfig(c.config)}, true
+}
+
+// Connectors returns the names of all connectors, sorted.
+func (s *Server) Connectors() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.names()
+}
+
+// Status returns the current status of a connector.
+func (s *Server) Status(name string) (kafkaconnect.Status, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.connectors[name]
+	if !ok {
+		return kafkaconnect.Status{}, false
+	}
+	return kafkaconnect.Status{
+		Name:      c.name,
+		Connector: kafkaconnect.ConnectorStatus{State: c.state, WorkerID: s.workerID, Trace: c.trace},
+		Tasks:     append([]kafkaconnect.Task{}, c.tasks...),
+	}, true
+}
+
+// FailTask moves a task of a connector to the FAILED state with the given stack trace.
+func (s *Server) FailTask(name string, task int, trace string) error {
+	return s.SetTaskState(name, task, StateFailed, trace)
+}
+
+// SetTaskState moves a task of a connector to an arbitrary state.
+func (s *Server) SetTaskState(name string, task int, state string, trace string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.connectors[name]
+	if !ok {
+		return fmt.Errorf("Connector %s not found", name)
+	}
+	if task < 0 || task >= len(c.tasks) {
+		return fmt.Errorf("Task %d of connector %s not found", task, name)
+	}
+	c.tasks[task].State = state
+	c.tasks[task].Trace = trace
+	return nil
+}
+
+// FailConnector moves a connector to the FAILED state with the given stack trace.
+func (s *Server) FailConnector(name string, trace string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.connectors[name]
+	if !ok {
+		return fmt.Errorf("Connector %s not found", name)
+	}
+	c.state = StateFailed
+	c.trace = trace
+	return nil
+}
+
+// SetOffsets replaces the committed offsets of a connector.
+func (s *Server) SetOffsets(name string, offsets []Offset) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.connectors[name]
+	if !ok {
+		return fmt.Errorf("Connector %s not found", name)
+	}
+	c.offsets = append([]Offset{}, offsets...)
+	return nil
+}
+
+// Offsets returns the committed offsets of a connector.
+func (s *Server) Offsets(name string) ([]Offset, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.connectors[name]
+	if !ok {
+		return nil, false
+	}
+	return append([]Offset{}, c.offsets...), true
+}
+
+// Requests returns every request received so far as "METHOD /path".
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.requests...)
+}
+
+func (s *Server) names() []string {
+	names := make([]string, 0, len(s.connectors))
+	for name := range s.connectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *Server) putConnector(name string, config map[string]string) (*connector, bool) {
+	c, exists := s.connectors[name]
+	if !exists {
+		c = &connector{name: name, state: StateRunning}
+		s.connectors[name] = c
+	}
+	c.config = copyConfig(config)
+	c.config["name"] = name
+	if c.state == StateFailed {
+		c.state = StateRunning
+	}
+	c.trace = ""
+	s.assignTasks(c)
+	return c, !exists
+}
+
+// assignTasks regenerates the tasks of a connector the way a worker does after a
+// reconfiguration.
+func (s *Server) assignTasks(c *connector) {
+	c.tasks = nil
+	if c.state == StateStopped {
+		return
+	}
+
+	count := 1
+	if n, err := strconv.Atoi(c.config["tasks.max"]); err == nil && n > 0 {
+		count = n
+	}
+
+	state := StateRunning
+	if c.state == StatePaused {
+		state = StatePaused
+	}
+
+	for i := 0; i < count; i++ {
+		c.tasks = append(c.tasks, kafkaconnect.Task{ID: i, State: state, WorkerID: s.workerID})
+	}
+}
+
+func (s *Server) connectorType(c *connector) string {
+	class := c.config["connector.class"]
+	for _, p := range s.plugins {
+		if p.Class == class || strings.HasSuffix(p.Class, "."+class) {
+			return p.Type
+		}
+	}
+	if strings.Contains(class, "Sink") {
+		return "sink"
+	}
+	return "source"
+}
+
+func (s *Server) connectorInfo(c *connector) connectorInfo {
+	info := connectorInfo{
+		Name:   c.name,
+		Config: copyConfig(c.config),
+		Tasks:  []taskID{},
+		Type:   s.connectorType(c),
+	}
+	for _, t := range c.tasks {
+		info.Tasks = append(info.Tasks, taskID{Connector: c.name, Task: t.ID})
+	}
+	return info
+}
+
+func (s *Server) connectorStatus(c *connector) connectorStatus {
+	status := connectorStatus{
+		Name: c.name,
+		Connector: stateInfo{
+			State:    c.state,
+			WorkerID: s.workerID,
+			Trace:    c.trace,
+		},
+		Tasks: []taskStatus{},
+		Type:  s.connectorType(c),
+	}
+	for _, t := range c.tasks {
+		status.Tasks = append(status.Tasks, taskStatus{ID: t.ID, State: t.State, WorkerID: t.WorkerID, Trace: t.Trace})
+	}
+	return status
+}
+
+func copyConfig(config map[string]string) map[string]string {
+	c := make(map[string]string, len(config))
+	for k, v := range config {
+		c[k] = v
+	}
+	return c
+}
+
+type connectorInfo struct {
+	Name   string            `json:"name"`
+	Config map[string]string `json:"config"`
+	Tasks  []taskID          `json:"tasks"`
+	Type   string            `json:"type"`
+}
+
+type taskID struct {
+	Connector string `json:"connector"`
+	Task      int    `json:"task"`
+}
+
+type stateInfo struct {
+	State    string `json:"state"`
+	WorkerID string `json:"worker_id"`
+	Trace    string `json:"trace,omitempty"`
+}
+
+type taskStatus struct {
+	ID       int    `json:"id"`
+	State    string `json:"state"`
+	WorkerID string `json:"worker_id"`
+	Trace    string `json:"trace,omitempty"`
+}
+
+type connectorStatus struct {
+	Name      string       `json:"name"`
+	Connector stateInfo    `json:"connector"`
+	Tasks     []taskStatus `json:"tasks"`
+	Type      string       `json:"type"`
+}
+
+type offsets struct {
+	Offsets []Offset `json:"offsets"`
+}
+
+type message struct {
+	Message string `json:"message"`
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, r.Method+" "+r.URL.EscapedPath())
+	latency := s.latency
+	fault := s.fire(r)
+	s.mu.Unlock()
+
+	if d := latency + fault.Delay; d > 0 {
+		time.Sleep(d)
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if fault.Drop {
+		dropConnection(w)
+		return
+	}
+
+	if fault.Status != 0 {
+		writeError(w, fault.Status, fault.Message)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.rebalancing && r.Method != http.MethodGet {
+		writeError(w, http.StatusConflict, rebalanceMessage)
+		return
+	}
+
+	segments, err := splitPath(r.URL.EscapedPath())
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	s.route(w, r, segments, body)
+}
+
+func splitPath(path string) ([]string, error) {
+	var segments []string
+	for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+		if segment == "" {
+			continue
+		}
+		unescaped, err := url.PathUnescape(segment)
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, unescaped)
+	}
+	return segments, nil
+}
+
+func (s *Server) route(w http.ResponseWriter, r *http.Request, segments []string, body []byte) {
+	method := r.Method
+	n := len(segments)
+
+	switch {
+	case n == 0 && method == http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]string{"version": s.version, "commit": "kafkaconnecttest", "kafka_cluster_id": "kafkaconnecttest"})
+	case n == 1 && segments[0] == "connector-plugins" && method == http.MethodGet:
+		writeJSON(w, http.StatusOK, s.plugins)
+	case n == 4 && segments[0] == "connector-plugins" && segments[2] == "config" && segments[3] == "validate" && method == http.MethodPut:
+		s.validate(w, segments[1], body)
+	case n >= 1 && segments[0] == "connectors":
+		s.routeConnectors(w, r, segments[1:], body)
+	default:
+		writeError(w, http.StatusNotFound, "HTTP 404 Not Found")
+	}
+}
+
+func (s *Server) routeConnectors(w http.ResponseWriter, r *http.Request, segments []string, body []byte) {
+	method := r.Method
+
+	if len(segments) == 0 {
+		switch method {
+		case http.MethodGet:
+			s.list(w, r)
+		case http.MethodPost:
+			s.create(w, body)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "HTTP 405 Method Not Allowed")
+		}
+		return
+	}
+
+	name := segments[0]
+	rest := strings.Join(segments[1:], "/")
+
+	if rest == "config" && method == http.MethodPut {
+		s.putConfig(w, name, body)
+		return
+	}
+
+	c, ok := s.connectors[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Connector %s not found", name))
+		return
+	}
+
+	switch {
+	case rest == "" && method == http.MethodGet:
+		writeJSON(w, http.StatusOK, s.connectorInfo(c))
+	case rest == "" && method == http.MethodDelete:
+		delete(s.connectors, name)
+		w.WriteHeader(http.StatusNoContent)
+	case rest == "config" && method == http.MethodGet:
+		writeJSON(w, http.StatusOK, c.config)
+	case rest == "status" && method == http.MethodGet:
+		writeJSON(w, http.StatusOK, s.connectorStatus(c))
+	case rest == "restart" && method == http.MethodPost:
+		s.restart(w, r, c)
+	case rest == "pause" && method == http.MethodPut:
+		s.setState(c, StatePaused)
+		w.WriteHeader(http.StatusAccepted)
+	case rest == "resume" && method == http.MethodPut:
+		s.setState(c, StateRunning)
+		w.WriteHeader(http.StatusAccepted)
+	case rest == "stop" && method == http.MethodPut && s.atLeast(3, 5):
+		s.setState(c, StateStopped)
+		w.WriteHeader(http.StatusAccepted)
+	case rest == "tasks" && method == http.MethodGet:
+		s.tasks(w, c)
+	case len(segments) == 4 && segments[1] == "tasks":
+		s.routeTask(w, method, c, segments[2], segments[3])
+	case rest == "topics" && method == http.MethodGet:
+		s.topics(w, c)
+	case rest == "topics/reset" && method == http.MethodPut:
+		w.WriteHeader(http.StatusOK)
+	case rest == "offsets" && !s.noOffsets && s.atLeast(3, 5):
+		s.routeOffsets(w, method, c, body)
+	default:
+		writeError(w, http.StatusNotFound, "HTTP 404 Not Found")
+	}
+}
+
+// atLeast reports whether the version of the server is major.minor or later.
+func (s *Server) atLeast(major int, minor int) bool {
+	return kafkaconnect.ServerInfo{Version: s.version}.AtLeast(major, minor)
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request) {
+	expand := r.URL.Query()["expand"]
+	if len(expand) == 0 {
+		writeJSON(w, http.StatusOK, s.names())
+		return
+	}
+
+	result := map[string]map[string]interface{}{}
+	for _, name := range s.names() {
+		c := s.connectors[name]
+		entry := map[string]interface{}{}
+		for _, e := range expand {
+			switch e {
+			case "status":
+				entry["status"] = s.connectorStatus(c)
+			case "info":
+				entry["info"] = s.connectorInfo(c)
+			}
+		}
+		result[name] = entry
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) create(w http.ResponseWriter, body []byte) {
+	var req kafkaconnect.Connector
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "Failed to deserialize connector: "+err.Error())
+		return
+	}
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "Connector name is required")
+		return
+	}
+	if req.Config == nil {
+		writeError(w, http.StatusBadRequest, "Connector config is required")
+		return
+	}
+	if _, exists := s.connectors[req.Name]; exists {
+		writeError(w, http.StatusConflict, fmt.Sprintf("Connector %s already exists", req.Name))
+		return
+	}
+	if msg := s.checkConfig(req.Config); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+	if req.InitialState != "" && !s.atLeast(3, 7) {
+		writeError(w, http.StatusBadRequest, "Unrecognized field \"initial_state\"")
+		return
+	}
+	switch req.InitialState {
+	case "", StateRunning, StatePaused, StateStopped:
+	default:
+		writeError(w, http.StatusBadRequest, "Invalid initial state "+req.InitialState+", expected RUNNING, PAUSED or STOPPED")
+		return
+	}
+
+	c, _ := s.putConnector(req.Name, req.Config)
+	if req.InitialState != "" {
+		s.setState(c, req.InitialState)
+	}
+	writeJSON(w, http.StatusCreated, s.connectorInfo(c))
+}
+
+func (s *Server) putConfig(w http.ResponseWriter, name string, body []byte) {
+	var config map[string]string
+	if err := json.Unmarshal(body, &config); err != nil {
+		writeError(w, http.StatusBadRequest, "Failed to deserialize connector config: "+err.Error())
+		return
+	}
+	if configName, ok := config["name"]; ok && configName != name {
+		writeError(w, http.StatusBadRequest, "Connector name configuration ("+configName+") doesn't match connector name in the URL ("+name+")")
+		return
+	}
+	if msg := s.checkConfig(config); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	c, created := s.putConnector(name, config)
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	writeJSON(w, status, s.connectorInfo(c))
+}
+
+// checkConfig mimics the minimal validation a worker does before accepting a config.
+func (s *Server) checkConfig(config map[string]string) string {
+	class := config["connector.class"]
+	if class == "" {
+		return "Connector config " + fmt.Sprint(config) + " contains no connector type"
+	}
+	if !s.hasPlugin(class) {
+		return "Failed to find any class that implements Connector and which name matches " + class
+	}
+	return ""
+}
+
+func (s *Server) hasPlugin(class string) bool {
+	for _, p := range s.plugins {
+		if p.Class == class || strings.HasSuffix(p.Class, "."+class) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) restart(w http.ResponseWriter, r *http.Request, c *connector) {
+	includeTasks := r.URL.Query().Get("includeTasks") == "true"
+	onlyFailed := r.URL.Query().Get("onlyFailed") == "true"
+
+	if !onlyFailed || c.state == StateFailed {
+		c.state = StateRunning
+		c.trace = ""
+	}
+
+	if !includeTasks {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	for i := range c.tasks {
+		if !onlyFailed || c.tasks[i].State == StateFailed {
+			c.tasks[i].State = StateRunning
+			c.tasks[i].Trace = ""
+		}
+	}
+	writeJSON(w, http.StatusAccepted, s.connectorStatus(c))
+}
+
+func (s *Server) setState(c *connector, state string) {
+	c.state = state
+	c.trace = ""
+	s.assignTasks(c)
+}
+
+func (s *Server) tasks(w http.ResponseWriter, c *connector) {
+	type taskInfo struct {
+		ID     taskID            `json:"id"`
+		Config map[string]string `json:"config"`
+	}
+	tasks := []taskInfo{}
+	for _, t := range c.tasks {
+		config := map[string]string{"task.class": c.config["connector.class"] + "Task"}
+		if topics, ok := c.config["topics"]; ok {
+			config["topics"] = topics
+		}
+		tasks = append(tasks, taskInfo{ID: taskID{Connector: c.name, Task: t.ID}, Config: config})
+	}
+	writeJSON(w, http.StatusOK, tasks)
+}
+
+func (s *Server) routeTask(w http.ResponseWriter, method string, c *connector, id string, action string) {
+	i, err := strconv.Atoi(id)
+	if err != nil || i < 0 || i >= len(c.tasks) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Task %s-%s not found", c.name, id))
+		return
+	}
+
+	switch {
+	case action == "status" && method == http.MethodGet:
+		t := c.tasks[i]
+		writeJSON(w, http.StatusOK, taskStatus{ID: t.ID, State: t.State, WorkerID: t.WorkerID, Trace: t.Trace})
+	case action == "restart" && method == http.MethodPost:
+		c.tasks[i].State = StateRunning
+		c.tasks[i].Trace = ""
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusNotFound, "HTTP 404 Not Found")
+	}
+}
+
+func (s *Server) topics(w http.ResponseWriter, c *connector) {
+	topics := []string{}
+	for _, topic := range strings.Split(c.config["topics"], ",") {
+		if topic = strings.TrimSpace(topic); topic != "" {
+			topics = append(topics, topic)
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]map[string][]string{c.name: {"topics": topics}})
+}
+
+func (s *Server) routeOffsets(w http.ResponseWriter, method string, c *connector, body []byte) {
+	switch method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, offsets{Offsets: append([]Offset{}, c.offsets...)})
+	case http.MethodPatch, http.MethodDelete:
+		if c.state != StateStopped {
+			writeError(w, http.StatusBadRequest, "Connectors must be in the STOPPED state before their offsets can be modified. This can be done for the specified connector by issuing a 'PUT' request to the '/connectors/"+c.name+"/stop' endpoint")
+			return
+		}
+		if method == http.MethodDelete {
+			c.offsets = nil
+			writeJSON(w, http.StatusOK, message{Message: "The offsets for this connector have been reset successfully"})
+			return
+		}
+		var req offsets
+		if err := json.Unmarshal(body, &req); err != nil {
+			writeError(w, http.StatusBadRequest, "Failed to deserialize offsets: "+err.Error())
+			return
+		}
+		c.offsets = mergeOffsets(c.offsets, req.Offsets)
+		writeJSON(w, http.StatusOK, message{Message: "The offsets for this connector have been altered successfully"})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "HTTP 405 Method Not Allowed")
+	}
+}
+
+func mergeOffsets(current []Offset, altered []Offset) []Offset {
+	result := append([]Offset{}, current...)
+	for _, a := range altered {
+		key, _ := json.Marshal(a.Partition)
+		replaced := false
+		for i, c := range result {
+			if k, _ := json.Marshal(c.Partition); string(k) == string(key) {
+				result[i] = a
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+func (s *Server) validate(w http.ResponseWriter, class string, body []byte) {
+	var config map[string]string
+	if err := json.Unmarshal(body, &config); err != nil {
+		writeError(w, http.StatusBadRequest, "Failed to deserialize connector config: "+err.Error())
+		return
+	}
+	if !s.hasPlugin(class) {
+		writeError(w, http.StatusBadRequest, "Failed to find any class that implements Connector and which name matches "+class)
+		return
+	}
+	writeJSON(w, http.StatusOK, validateConfig(class, config))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, kafkaconnect.Error{ErrorCode: status, Message: msg})
+}
@@ -0,0 +1,232 @@
+package kafkaconnecttest_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/walmartdigital/go-kaya/pkg/kafkaconnect"
+	"github.com/walmartdigital/go-kaya/pkg/kafkaconnect/kafkaconnecttest"
+)
+
+func TestAll(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "KafkaConnectTest")
+}
+
+var _ = Describe("Fake Kafka Connect server", func() {
+	var (
+		server    *kafkaconnecttest.Server
+		kcc       *kafkaconnect.Client
+		connector kafkaconnect.Connector
+	)
+
+	BeforeEach(func() {
+		var err error
+		server = kafkaconnecttest.NewServer()
+		kcc, err = kafkaconnect.New(server.URL)
+		Expect(err).To(BeNil())
+
+		connector = kafkaconnect.Connector{
+			Name: "logging",
+			Config: map[string]string{
+				"connector.class": "io.confluent.connect.elasticsearch.ElasticsearchSinkConnector",
+				"tasks.max":       "2",
+				"topics":          "_dumblogger.logs",
+				"topic.index.map": "_dumblogger.logs:<logs-pd-dumblogger-{now/d}>",
+				"connection.url":  "http://elasticsearch-master.default.svc.cluster.local:9200",
+			},
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should create, read, update and delete a connector", func() {
+		resp, err := kcc.Create(connector)
+		Expect(err).To(BeNil())
+		Expect(resp.Result).To(Equal("success"))
+		Expect(resp.Payload.(kafkaconnect.Connector).Config).To(HaveKeyWithValue("name", "logging"))
+
+		resp, err = kcc.Create(connector)
+		Expect(err).NotTo(BeNil())
+		Expect(resp.Result).To(Equal("conflict"))
+
+		resp, err = kcc.Read("logging")
+		Expect(err).To(BeNil())
+		Expect(resp.Payload.(map[string]string)).To(HaveKeyWithValue("tasks.max", "2"))
+
+		connector.Config["tasks.max"] = "3"
+		resp, err = kcc.Update(connector)
+		Expect(err).To(BeNil())
+		Expect(resp.Result).To(Equal("success"))
+
+		status, ok := server.Status("logging")
+		Expect(ok).To(BeTrue())
+		Expect(status.GetTaskCount()).To(Equal(3))
+
+		resp, err = kcc.Delete("logging")
+		Expect(err).To(BeNil())
+		Expect(resp.Result).To(Equal("success"))
+		Expect(server.Connectors()).To(BeEmpty())
+
+		resp, err = kcc.Read("logging")
+		Expect(err).NotTo(BeNil())
+		Expect(resp.Result).To(Equal("notfound"))
+	})
+
+	It("should report and restart failed tasks", func() {
+		server.AddConnector(connector)
+		Expect(server.FailTask("logging", 1, "java.lang.NullPointerException")).To(Succeed())
+
+		resp, err := kcc.GetStatus("logging")
+		Expect(err).To(BeNil())
+		status := resp.Payload.(kafkaconnect.Status)
+		Expect(status.GetActiveTasksCount()).To(Equal(1))
+		Expect(status.GetFailedTasks()).To(Equal([]int{1}))
+		Expect(status.Tasks[1].Trace).To(Equal("java.lang.NullPointerException"))
+
+		resp, err = kcc.RestartTask("logging", 1)
+		Expect(err).To(BeNil())
+		Expect(resp.Result).To(Equal("success"))
+
+		status, _ = server.Status("logging")
+		Expect(status.GetFailedTasks()).To(BeEmpty())
+	})
+
+	It("should restart a failed connector", func() {
+		server.AddConnector(connector)
+		Expect(server.FailConnector("logging", "org.apache.kafka.connect.errors.ConnectException")).To(Succeed())
+
+		resp, err := kcc.GetStatus("logging")
+		Expect(err).To(BeNil())
+		Expect(resp.Payload.(kafkaconnect.Status).IsConnectorFailed()).To(BeTrue())
+
+		_, err = kcc.RestartConnector("logging")
+		Expect(err).To(BeNil())
+
+		status, _ := server.Status("logging")
+		Expect(status.IsConnectorFailed()).To(BeFalse())
+	})
+
+	It("should reject writes while rebalancing", func() {
+		server.SetRebalancing(true)
+
+		resp, err := kcc.Create(connector)
+		Expect(err).NotTo(BeNil())
+		Expect(resp.Result).To(Equal("conflict"))
+
+		server.SetRebalancing(false)
+
+		_, err = kcc.Create(connector)
+		Expect(err).To(BeNil())
+	})
+
+	It("should delay responses by the configured latency", func() {
+		server.SetLatency(100 * time.Millisecond)
+
+		start := time.Now()
+		_, _ = kcc.Read("logging")
+		Expect(time.Since(start)).To(BeNumerically(">=", 100*time.Millisecond))
+	})
+
+	It("should reject connectors with unknown plugins", func() {
+		connector.Config["connector.class"] = "com.example.UnknownConnector"
+
+		resp, err := kcc.Create(connector)
+		Expect(err).To(BeAssignableToTypeOf(&kafkaconnect.ValidationError{}))
+		Expect(err.Error()).To(ContainSubstring("Failed to find any class that implements Connector"))
+		Expect(resp.Result).To(Equal("invalid"))
+	})
+
+	It("should pause and resume connectors", func() {
+		server.AddConnector(connector)
+
+		status := doRequest(http.MethodPut, server.URL+"/connectors/logging/pause", "")
+		Expect(status).To(Equal(http.StatusAccepted))
+		s, _ := server.Status("logging")
+		Expect(s.Connector.State).To(Equal(kafkaconnecttest.StatePaused))
+		Expect(s.Tasks[0].State).To(Equal(kafkaconnecttest.StatePaused))
+
+		status = doRequest(http.MethodPut, server.URL+"/connectors/logging/resume", "")
+		Expect(status).To(Equal(http.StatusAccepted))
+		s, _ = server.Status("logging")
+		Expect(s.GetActiveTasksCount()).To(Equal(2))
+	})
+
+	It("should only alter offsets of stopped connectors", func() {
+		server.AddConnector(connector)
+		body := `{"offsets":[{"partition":{"kafka_topic":"_dumblogger.logs","kafka_partition":0},"offset":{"kafka_offset":42}}]}`
+
+		Expect(doRequest(http.MethodPatch, server.URL+"/connectors/logging/offsets", body)).To(Equal(http.StatusBadRequest))
+		Expect(doRequest(http.MethodPut, server.URL+"/connectors/logging/stop", "")).To(Equal(http.StatusAccepted))
+		Expect(doRequest(http.MethodPatch, server.URL+"/connectors/logging/offsets", body)).To(Equal(http.StatusOK))
+
+		offsets, ok := server.Offsets("logging")
+		Expect(ok).To(BeTrue())
+		Expect(offsets).To(HaveLen(1))
+		Expect(offsets[0].Offset).To(HaveKeyWithValue("kafka_offset", BeNumerically("==", 42)))
+	})
+
+	It("should only serve the APIs of the configured version", func() {
+		server.AddConnector(connector)
+		stopped := `{"name":"stopped","config":{"connector.class":"io.confluent.connect.elasticsearch.ElasticsearchSinkConnector","topics":"a"},"initial_state":"STOPPED"}`
+
+		server.SetVersion("3.6.1")
+		Expect(doRequest(http.MethodGet, server.URL+"/connectors/logging/offsets", "")).To(Equal(http.StatusOK))
+		Expect(doRequest(http.MethodPost, server.URL+"/connectors", stopped)).To(Equal(http.StatusBadRequest))
+
+		server.SetVersion("3.4.0")
+		Expect(doRequest(http.MethodGet, server.URL+"/connectors/logging/offsets", "")).To(Equal(http.StatusNotFound))
+		Expect(doRequest(http.MethodPut, server.URL+"/connectors/logging/stop", "")).To(Equal(http.StatusNotFound))
+
+		server.SetVersion(kafkaconnecttest.Version)
+		Expect(doRequest(http.MethodPost, server.URL+"/connectors", stopped)).To(Equal(http.StatusCreated))
+		status, ok := server.Status("stopped")
+		Expect(ok).To(BeTrue())
+		Expect(status.Connector.State).To(Equal(kafkaconnecttest.StateStopped))
+	})
+
+	It("should validate connector configurations", func() {
+		resp, err := http.DefaultClient.Do(newRequest(
+			http.MethodPut,
+			server.URL+"/connector-plugins/ElasticsearchSinkConnector/config/validate",
+			`{"connector.class":"ElasticsearchSinkConnector","name":"logging","topics":"a","topics.regex":"a.*"}`,
+		))
+		Expect(err).To(BeNil())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		var result struct {
+			ErrorCount int `json:"error_count"`
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		Expect(json.Unmarshal(body, &result)).To(Succeed())
+		Expect(result.ErrorCount).To(Equal(2))
+	})
+
+	It("should record every request", func() {
+		_, _ = kcc.Read("logging")
+		Expect(server.Requests()).To(Equal([]string{"GET /connectors/logging/config"}))
+	})
+})
+
+func newRequest(method string, url string, body string) *http.Request {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	Expect(err).To(BeNil())
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func doRequest(method string, url string, body string) int {
+	resp, err := http.DefaultClient.Do(newRequest(method, url, body))
+	Expect(err).To(BeNil())
+	resp.Body.Close()
+	return resp.StatusCode
+}
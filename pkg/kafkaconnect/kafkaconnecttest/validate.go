@@ -0,0 +1,117 @@
+package kafkaconnecttest
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type configDefinition struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Required bool   `json:"required"`
+	Group    string `json:"group"`
+}
+
+type configValue struct {
+	Name              string   `json:"name"`
+	Value             *string  `json:"value"`
+	RecommendedValues []string `json:"recommended_values"`
+	Errors            []string `json:"errors"`
+	Visible           bool     `json:"visible"`
+}
+
+type configInfo struct {
+	Definition configDefinition `json:"definition"`
+	Value      configValue      `json:"value"`
+}
+
+type configInfos struct {
+	Name       string       `json:"name"`
+	ErrorCount int          `json:"error_count"`
+	Groups     []string     `json:"groups"`
+	Configs    []configInfo `json:"configs"`
+}
+
+// validateConfig mimics the response of PUT /connector-plugins/{class}/config/validate for
+// the settings common to every connector.
+func validateConfig(class string, config map[string]string) configInfos {
+	result := configInfos{Name: class, Groups: []string{"Common"}, Configs: []configInfo{}}
+
+	errors := map[string][]string{}
+	addError := func(key string, msg string) {
+		errors[key] = append(errors[key], msg)
+	}
+
+	if config["name"] == "" {
+		addError("name", "Missing required configuration \"name\" which has no default value.")
+	}
+
+	if c := config["connector.class"]; c != "" && c != class && !strings.HasSuffix(class, "."+c) {
+		addError("connector.class", "Connector class "+c+" does not match the validated plugin "+class)
+	}
+
+	if v, ok := config["tasks.max"]; ok {
+		if n, err := strconv.Atoi(v); err != nil || n < 1 {
+			addError("tasks.max", "Invalid value "+v+" for configuration tasks.max: Value must be at least 1")
+		}
+	}
+
+	if strings.Contains(class, "Sink") {
+		_, hasTopics := config["topics"]
+		_, hasRegex := config["topics.regex"]
+		switch {
+		case hasTopics && hasRegex:
+			msg := "Must configure one of topics or topics.regex, but not both"
+			addError("topics", msg)
+			addError("topics.regex", msg)
+		case !hasTopics && !hasRegex:
+			msg := "Must configure one of topics or topics.regex"
+			addError("topics", msg)
+			addError("topics.regex", msg)
+		}
+	}
+
+	keys := map[string]bool{"name": true, "connector.class": true, "tasks.max": true}
+	for key := range config {
+		keys[key] = true
+	}
+	for key := range errors {
+		keys[key] = true
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	for _, key := range sorted {
+		var value *string
+		if v, ok := config[key]; ok {
+			value = &v
+		}
+		errs := errors[key]
+		if errs == nil {
+			errs = []string{}
+		}
+		result.ErrorCount += len(errs)
+		result.Configs = append(result.Configs, configInfo{
+			Definition: configDefinition{
+				Name:     key,
+				Type:     "STRING",
+				Required: key == "name" || key == "connector.class",
+				Group:    "Common",
+			},
+			Value: configValue{
+				Name:              key,
+				Value:             value,
+				RecommendedValues: []string{},
+				Errors:            errs,
+				Visible:           true,
+			},
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,178 @@
+package kafkaconnect
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/walmartdigital/go-kaya/pkg/client"
+	"github.com/walmartdigital/go-kaya/pkg/validator"
+)
+
+// States of connectors and tasks.
+const (
+	StateRunning    = "RUNNING"
+	StatePaused     = "PAUSED"
+	StateStopped    = "STOPPED"
+	StateFailed     = "FAILED"
+	StateUnassigned = "UNASSIGNED"
+)
+
+// Offset is the offset of a source partition, or of a topic partition for sink connectors.
+type Offset struct {
+	Partition map[string]interface{} `json:"partition"`
+	Offset    map[string]interface{} `json:"offset"`
+}
+
+// Offsets are the committed offsets of a connector.
+type Offsets struct {
+	Offsets []Offset `json:"offsets"`
+}
+
+// ServerInfo is the version of a Kafka Connect worker, as reported by its root endpoint.
+type ServerInfo struct {
+	Version        string `json:"version"`
+	Commit         string `json:"commit"`
+	KafkaClusterID string `json:"kafka_cluster_id"`
+}
+
+// AtLeast reports whether the worker runs Kafka major.minor or later. Versions that cannot
+// be parsed are assumed to be recent.
+func (i ServerInfo) AtLeast(major int, minor int) bool {
+	parts := strings.SplitN(i.Version, ".", 3)
+	if len(parts) < 2 {
+		return true
+	}
+	ma, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return true
+	}
+	mi, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return true
+	}
+	return ma > major || (ma == major && mi >= minor)
+}
+
+// ServerInfo gets the version of the worker, to tell which of the APIs below it supports.
+// The returned payload is a ServerInfo.
+func (kcc Client) ServerInfo() (*Response, error) {
+	var info ServerInfo
+	status, body, err := kcc.httpClient.Get("/")
+	kcc.logResult("ServerInfo", "", status, err)
+
+	if err != nil {
+		return &Response{Result: "error"}, fmt.Errorf("Error executing ServerInfo on Kafka Connect: %s", err.Error())
+	}
+
+	switch status {
+	case 200:
+		err := json.Unmarshal(*body, &info)
+		if err == nil {
+			response := new(Response)
+			response.Result = "success"
+			response.Payload = info
+			return response, nil
+		}
+		return &Response{Result: "error"}, errors.New("Failed to deserialize Kafka Connect response")
+	default:
+		return HandleNonOKResponse(status, body)
+	}
+}
+
+// Pause suspends a connector and its tasks. Kafka Connect pauses connectors asynchronously,
+// use GetStatus to know when it is done.
+func (kcc Client) Pause(connector string) (*Response, error) {
+	return kcc.changeState("Pause", connector, "pause")
+}
+
+// Resume resumes a paused or stopped connector.
+func (kcc Client) Resume(connector string) (*Response, error) {
+	return kcc.changeState("Resume", connector, "resume")
+}
+
+// Stop shuts down a connector and its tasks, leaving only its config. Its offsets can then
+// be altered. Requires Kafka Connect 3.5.
+func (kcc Client) Stop(connector string) (*Response, error) {
+	return kcc.changeState("Stop", connector, "stop")
+}
+
+func (kcc Client) changeState(operation string, connector string, action string) (*Response, error) {
+	if kcc.namePolicy().Valid(connector) {
+		status, body, err := kcc.httpClient.Put("/connectors/"+validator.EscapeName(connector)+"/"+action, []byte{})
+		kcc.logResult(operation, connector, status, err)
+
+		if err != nil {
+			return &Response{Result: "error"}, fmt.Errorf("Error executing %s on Kafka Connect: %s", operation, err.Error())
+		}
+
+		switch status {
+		case 202, 204:
+			response := new(Response)
+			response.Result = "success"
+			return response, nil
+		default:
+			return HandleNonOKResponse(status, body)
+		}
+	}
+	return nil, kcc.namePolicy().Check(connector)
+}
+
+// GetOffsets gets the committed offsets of a connector. The returned payload is an Offsets.
+// Requires Kafka Connect 3.5, older workers answer with a "notfound" result.
+func (kcc Client) GetOffsets(connector string) (*Response, error) {
+	var offsets Offsets
+	if kcc.namePolicy().Valid(connector) {
+		status, body, err := kcc.httpClient.Get("/connectors/" + validator.EscapeName(connector) + "/offsets")
+		kcc.logResult("GetOffsets", connector, status, err)
+
+		if err != nil {
+			return &Response{Result: "error"}, fmt.Errorf("Error executing GetOffsets on Kafka Connect: %s", err.Error())
+		}
+
+		switch status {
+		case 200:
+			err := json.Unmarshal(*body, &offsets)
+			if err == nil {
+				response := new(Response)
+				response.Result = "success"
+				response.Payload = offsets
+				return response, nil
+			}
+			return &Response{Result: "error"}, errors.New("Failed to deserialize Kafka Connect response")
+		default:
+			return HandleNonOKResponse(status, body)
+		}
+	}
+	return nil, kcc.namePolicy().Check(connector)
+}
+
+// AlterOffsets sets the offsets of the partitions listed in offsets. The connector must be
+// stopped. The HTTP client must be a client.Patcher.
+func (kcc Client) AlterOffsets(connector string, offsets Offsets) (*Response, error) {
+	if kcc.namePolicy().Valid(connector) {
+		offsetsBytes, err := json.Marshal(offsets)
+		if err != nil {
+			return &Response{Result: "error"}, errors.New("Failed to serialize connector offsets")
+		}
+
+		status, body, err := client.Patch(kcc.httpClient, "/connectors/"+validator.EscapeName(connector)+"/offsets", offsetsBytes)
+		kcc.logResult("AlterOffsets", connector, status, err)
+
+		if err != nil {
+			return &Response{Result: "error"}, fmt.Errorf("Error executing AlterOffsets on Kafka Connect: %s", err.Error())
+		}
+
+		switch status {
+		case 200:
+			response := new(Response)
+			response.Result = "success"
+			return response, nil
+		default:
+			return HandleNonOKResponse(status, body)
+		}
+	}
+	return nil, kcc.namePolicy().Check(connector)
+}
@@ -0,0 +1,107 @@
+package kafkaconnect_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/walmartdigital/go-kaya/pkg/kafkaconnect"
+	"github.com/walmartdigital/go-kaya/pkg/kafkaconnect/kafkaconnecttest"
+)
+
+var _ = Describe("Connector lifecycle", func() {
+	var server *kafkaconnecttest.Server
+
+	BeforeEach(func() {
+		server = kafkaconnecttest.NewServer()
+		server.AddConnector(kafkaconnect.Connector{Name: "orders", Config: map[string]string{
+			"connector.class": "org.apache.kafka.connect.file.FileStreamSourceConnector",
+			"tasks.max":       "1",
+		}})
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	state := func(name string) string {
+		status, _ := server.Status(name)
+		return status.Connector.State
+	}
+
+	It("should pause, stop and resume connectors", func() {
+		kcc, err := kafkaconnect.New(server.URL)
+		Expect(err).To(BeNil())
+
+		resp, err := kcc.Pause("orders")
+		Expect(err).To(BeNil())
+		Expect(resp.Result).To(Equal("success"))
+		Expect(state("orders")).To(Equal(kafkaconnect.StatePaused))
+
+		_, err = kcc.Stop("orders")
+		Expect(err).To(BeNil())
+		Expect(state("orders")).To(Equal(kafkaconnect.StateStopped))
+
+		_, err = kcc.Resume("orders")
+		Expect(err).To(BeNil())
+		Expect(state("orders")).To(Equal(kafkaconnect.StateRunning))
+
+		resp, err = kcc.Pause("payments")
+		Expect(err).To(HaveOccurred())
+		Expect(resp.Result).To(Equal("notfound"))
+	})
+
+	It("should create connectors in their initial state", func() {
+		kcc, err := kafkaconnect.New(server.URL)
+		Expect(err).To(BeNil())
+		_, err = kcc.Create(kafkaconnect.Connector{
+			Name:         "payments",
+			Config:       map[string]string{"connector.class": "org.apache.kafka.connect.file.FileStreamSourceConnector"},
+			InitialState: kafkaconnect.StateStopped,
+		})
+		Expect(err).To(BeNil())
+		Expect(state("payments")).To(Equal(kafkaconnect.StateStopped))
+	})
+
+	It("should read and alter the offsets of stopped connectors", func() {
+		kcc, err := kafkaconnect.New(server.URL)
+		Expect(err).To(BeNil())
+		offsets := kafkaconnect.Offsets{Offsets: []kafkaconnect.Offset{{
+			Partition: map[string]interface{}{"filename": "/var/log/orders.log"},
+			Offset:    map[string]interface{}{"position": float64(42)},
+		}}}
+
+		resp, err := kcc.AlterOffsets("orders", offsets)
+		Expect(err).To(MatchError(ContainSubstring("must be in the STOPPED state")))
+		Expect(resp.Result).To(Equal("unspecified"))
+
+		_, err = kcc.Stop("orders")
+		Expect(err).To(BeNil())
+		_, err = kcc.AlterOffsets("orders", offsets)
+		Expect(err).To(BeNil())
+
+		resp, err = kcc.GetOffsets("orders")
+		Expect(err).To(BeNil())
+		Expect(resp.Payload).To(Equal(offsets))
+	})
+
+	It("should answer notfound when the worker does not support offsets", func() {
+		server.SetOffsetsSupported(false)
+		kcc, err := kafkaconnect.New(server.URL)
+		Expect(err).To(BeNil())
+		resp, err := kcc.GetOffsets("orders")
+		Expect(err).To(HaveOccurred())
+		Expect(resp.Result).To(Equal("notfound"))
+	})
+
+	It("should read the version of the worker", func() {
+		server.SetVersion("3.6.1")
+		kcc, err := kafkaconnect.New(server.URL)
+		Expect(err).To(BeNil())
+		resp, err := kcc.ServerInfo()
+		Expect(err).To(BeNil())
+		info := resp.Payload.(kafkaconnect.ServerInfo)
+		Expect(info.Version).To(Equal("3.6.1"))
+		Expect(info.AtLeast(3, 5)).To(BeTrue())
+		Expect(info.AtLeast(3, 7)).To(BeFalse())
+		Expect(kafkaconnect.ServerInfo{Version: "4.0.0"}.AtLeast(3, 7)).To(BeTrue())
+	})
+})
@@ -0,0 +1,95 @@
+package kafkaconnect_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/walmartdigital/go-kaya/pkg/kafkaconnect"
+	"github.com/walmartdigital/go-kaya/pkg/kafkaconnect/kafkaconnecttest"
+	"github.com/walmartdigital/go-kaya/pkg/validator"
+)
+
+var _ = Describe("Connector names", func() {
+	var (
+		server *kafkaconnecttest.Server
+		config map[string]string
+	)
+
+	BeforeEach(func() {
+		server = kafkaconnecttest.NewServer()
+		config = map[string]string{
+			"connector.class": "io.confluent.connect.elasticsearch.ElasticsearchSinkConnector",
+			"topics":          "_ims.logs",
+			"connection.url":  "http://elasticsearch:9200",
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should accept names derived from topics", func() {
+		kcc, err := kafkaconnect.New(server.URL)
+		Expect(err).To(BeNil())
+
+		resp, err := kcc.Create(kafkaconnect.Connector{Name: "_ims.logs-sink", Config: config})
+		Expect(err).To(BeNil())
+		Expect(resp.Result).To(Equal("success"))
+
+		resp, err = kcc.GetStatus("_ims.logs-sink")
+		Expect(err).To(BeNil())
+		Expect(resp.Result).To(Equal("success"))
+	})
+
+	It("should reject names outside of the policy", func() {
+		kcc, err := kafkaconnect.New(server.URL, kafkaconnect.WithNamePolicy(validator.StrictNamePolicy))
+		Expect(err).To(BeNil())
+
+		resp, err := kcc.Delete("team/logs sink")
+		Expect(resp).To(BeNil())
+		Expect(err).To(MatchError(`Invalid connector name "team/logs sink": must match ^[A-Za-z0-9._-]+$`))
+
+		_, err = kcc.Create(kafkaconnect.Connector{Name: "", Config: config})
+		Expect(err).To(MatchError(`Invalid connector name "": must not be empty`))
+		Expect(server.Requests()).To(BeEmpty())
+	})
+
+	It("should escape names in request paths", func() {
+		kcc, err := kafkaconnect.New(server.URL)
+		Expect(err).To(BeNil())
+
+		name := "team/logs sink?v=2"
+		_, err = kcc.Create(kafkaconnect.Connector{Name: name, Config: config})
+		Expect(err).To(BeNil())
+
+		resp, err := kcc.Read(name)
+		Expect(err).To(BeNil())
+		Expect(resp.Payload.(map[string]string)["topics"]).To(Equal("_ims.logs"))
+
+		_, err = kcc.RestartConnector(name)
+		Expect(err).To(BeNil())
+		_, err = kcc.Delete(name)
+		Expect(err).To(BeNil())
+		Expect(server.Connectors()).To(BeEmpty())
+
+		Expect(server.Requests()).To(ContainElement("GET /connectors/team%2Flogs%20sink%3Fv=2/config"))
+		Expect(server.Requests()).To(ContainElement("DELETE /connectors/team%2Flogs%20sink%3Fv=2"))
+	})
+
+	It("should use custom policies", func() {
+		policy := validator.NamePolicy{MaxLength: 8}
+		kcc, err := kafkaconnect.New(server.URL, kafkaconnect.WithNamePolicy(policy))
+		Expect(err).To(BeNil())
+
+		_, err = kcc.Read("logs-sink-eu")
+		Expect(err).To(MatchError(`Invalid connector name "logs-sink-eu": must not be longer than 8 bytes`))
+
+		v := validator.New(validator.WithNamePolicy(policy))
+		report := v.Validate(map[string]string{"name": "logs-sink-eu", "connector.class": "com.example.LogsSink"})
+		Expect(report.Violations).To(ConsistOf(validator.Violation{
+			Key:     "name",
+			Rule:    "connectorname",
+			Value:   "logs-sink-eu",
+			Message: "Invalid value for 'name': must not be longer than 8 bytes",
+		}))
+	})
+})
@@ -0,0 +1,197 @@
+package kafkaconnect
+
+import (
+	"crypto/tls"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/walmartdigital/go-kaya/pkg/client"
+	"github.com/walmartdigital/go-kaya/pkg/validator"
+)
+
+// Option configures optional behaviour of a Client.
+type Option func(*options)
+
+type options struct {
+	logger     logr.Logger
+	scheme     string
+	httpConfig client.HTTPClientConfig
+	factory    client.HTTPClientFactory
+	secrets    *SecretResolver
+	redactor   *Redactor
+	failover   []string
+	names      *validator.NamePolicy
+	validation ValidationMode
+	validator  *validator.Validator
+}
+
+func (o *options) setHeader(key string, value string) {
+	headers := make(map[string]string, len(o.httpConfig.Headers)+1)
+	for k, v := range o.httpConfig.Headers {
+		headers[k] = v
+	}
+	headers[key] = value
+	o.httpConfig.Headers = headers
+}
+
+// WithLogger sets the logger used by the client. Operations are logged with the
+// "operation", "connector" and "status" keys. Logging is disabled by default.
+func WithLogger(logger logr.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithHTTPClientConfig replaces the whole HTTP client configuration. As it overrides
+// settings made by other options it should be given first.
+func WithHTTPClientConfig(config client.HTTPClientConfig) Option {
+	return func(o *options) {
+		o.httpConfig = config
+	}
+}
+
+// WithHTTPClientFactory sets the factory used to build the underlying HTTP client.
+// Defaults to client.SimpleHTTPClientFactory, restyclient.RestyClientFactory is an
+// alternative built on go-resty.
+func WithHTTPClientFactory(factory client.HTTPClientFactory) Option {
+	return func(o *options) {
+		o.factory = factory
+	}
+}
+
+// WithScheme sets the URL scheme used when the base URL does not contain one.
+// Defaults to "http", or "https" when a TLS configuration is given.
+func WithScheme(scheme string) Option {
+	return func(o *options) {
+		o.scheme = scheme
+	}
+}
+
+// WithTLSConfig sets the TLS configuration used to reach Kafka Connect.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(o *options) {
+		o.httpConfig.TLSConfig = config
+	}
+}
+
+// WithBasicAuth authenticates every request with HTTP basic auth.
+func WithBasicAuth(username string, password string) Option {
+	return func(o *options) {
+		o.httpConfig.AuthType = client.BasicAuth
+		o.httpConfig.Creds = map[string]string{"username": username, "password": password}
+	}
+}
+
+// WithTokenAuth authenticates every request with a bearer token.
+func WithTokenAuth(token string) Option {
+	return func(o *options) {
+		o.httpConfig.AuthType = client.TokenAuth
+		o.httpConfig.Creds = map[string]string{"token": token}
+	}
+}
+
+// WithTimeout bounds every call made by the client, including its retries.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.httpConfig.Timeout = timeout
+	}
+}
+
+// WithAttemptTimeout bounds every HTTP attempt made by the client.
+func WithAttemptTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.httpConfig.AttemptTimeout = timeout
+	}
+}
+
+// WithRetryPolicy retries failed requests up to count times, waiting between wait and
+// maxWait between attempts. Requests are retried whenever condition returns true, and on
+// errors as described by client.RetryMiddleware.
+func WithRetryPolicy(count int, wait time.Duration, maxWait time.Duration, condition client.RetryConditionFunc) Option {
+	return func(o *options) {
+		o.httpConfig.RetryCount = count
+		o.httpConfig.RetryWaitTime = wait
+		o.httpConfig.RetryWaitMaxTime = maxWait
+		o.httpConfig.RetryCondition = condition
+	}
+}
+
+// WithMetrics reports every HTTP attempt to recorder.
+func WithMetrics(recorder client.MetricsRecorder) Option {
+	return func(o *options) {
+		o.httpConfig.Metrics = recorder
+	}
+}
+
+// WithMiddlewares appends middlewares to the HTTP client chain.
+func WithMiddlewares(middlewares ...client.Middleware) Option {
+	return func(o *options) {
+		o.httpConfig.Middlewares = append(o.httpConfig.Middlewares, middlewares...)
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(o *options) {
+		o.setHeader("User-Agent", userAgent)
+	}
+}
+
+// WithHeader sets an arbitrary header sent with every request.
+func WithHeader(key string, value string) Option {
+	return func(o *options) {
+		o.setHeader(key, value)
+	}
+}
+
+// WithSecretResolver resolves config provider references such as ${env:ES_PASSWORD} in
+// connector configurations before Create, Update and Apply send them.
+func WithSecretResolver(resolver *SecretResolver) Option {
+	return func(o *options) {
+		o.secrets = resolver
+	}
+}
+
+// WithRedactor sets the Redactor hiding sensitive config values from errors returned by
+// the client. Defaults to DefaultRedactor.
+func WithRedactor(redactor *Redactor) Option {
+	return func(o *options) {
+		o.redactor = redactor
+	}
+}
+
+// WithFailoverURLs adds other workers of the same cluster, tried in order when the base
+// URL cannot be reached. See client.FailoverClient.
+func WithFailoverURLs(urls ...string) Option {
+	return func(o *options) {
+		o.failover = append(o.failover, urls...)
+	}
+}
+
+// WithNamePolicy sets the policy connector names must comply with before any request is
+// sent. Defaults to validator.DefaultNamePolicy, which accepts every name Kafka Connect
+// does; validator.StrictNamePolicy only accepts names that never need escaping. Names are
+// escaped in request paths either way.
+func WithNamePolicy(policy validator.NamePolicy) Option {
+	return func(o *options) {
+		o.names = &policy
+	}
+}
+
+// WithValidation sets how Create, Update and Apply validate connector configs before
+// sending them. Defaults to ValidationLocal for New and ValidationOff for NewClient. Invalid
+// configs are not sent and a *ValidationError is returned, as for writes the worker rejects
+// with a 400 whatever the mode.
+func WithValidation(mode ValidationMode) Option {
+	return func(o *options) {
+		o.validation = mode
+	}
+}
+
+// WithValidator sets the validator used by local validation, e.g. one created with
+// validator.WithRegistry to check custom connector classes.
+func WithValidator(v *validator.Validator) Option {
+	return func(o *options) {
+		o.validator = v
+	}
+}
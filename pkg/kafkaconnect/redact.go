@@ -0,0 +1,179 @@
+package kafkaconnect
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// RedactedValue replaces sensitive values, matching what Kafka Connect shows for
+// PASSWORD type settings.
+const RedactedValue = "[hidden]"
+
+// DefaultRedactPatterns are the config key patterns considered sensitive by default.
+var DefaultRedactPatterns = []string{"*password*", "*secret*", "*.key", "sasl.jaas.config"}
+
+// DefaultRedactor is used by Connector.String and by clients created without WithRedactor.
+var DefaultRedactor = NewRedactor()
+
+// Redactor masks the values of sensitive config keys. Keys are matched case-insensitively
+// against path.Match patterns. The zero value hides nothing.
+type Redactor struct {
+	patterns []string
+}
+
+// NewRedactor returns a Redactor for the given key patterns, or for
+// DefaultRedactPatterns when none is given.
+func NewRedactor(patterns ...string) *Redactor {
+	if len(patterns) == 0 {
+		patterns = DefaultRedactPatterns
+	}
+	r := &Redactor{}
+	for _, p := range patterns {
+		r.patterns = append(r.patterns, strings.ToLower(p))
+	}
+	return r
+}
+
+// Patterns returns the key patterns of the redactor.
+func (r *Redactor) Patterns() []string {
+	return append([]string{}, r.patterns...)
+}
+
+// IsSensitive reports whether the value of key must be hidden.
+func (r *Redactor) IsSensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, p := range r.patterns {
+		if ok, _ := path.Match(p, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactConfig returns a copy of config with sensitive values replaced by RedactedValue.
+func (r *Redactor) RedactConfig(config map[string]string) map[string]string {
+	if config == nil {
+		return nil
+	}
+	redacted := make(map[string]string, len(config))
+	for k, v := range config {
+		if r.IsSensitive(k) && v != "" {
+			v = RedactedValue
+		}
+		redacted[k] = v
+	}
+	return redacted
+}
+
+// RedactConnector returns a copy of connector with sensitive values replaced.
+func (r *Redactor) RedactConnector(connector Connector) Connector {
+	return Connector{Name: connector.Name, Config: r.RedactConfig(connector.Config), InitialState: connector.InitialState}
+}
+
+// RedactString replaces every sensitive value of config found in s, e.g. in an error
+// message echoed by Kafka Connect.
+func (r *Redactor) RedactString(s string, config map[string]string) string {
+	values := make([]string, 0)
+	for k, v := range config {
+		if v != "" && r.IsSensitive(k) {
+			values = append(values, v)
+		}
+	}
+	// Replace longer values first so a value containing another one is fully hidden.
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+	for _, v := range values {
+		s = strings.Replace(s, v, RedactedValue, -1)
+	}
+	return s
+}
+
+// RedactError returns err with every sensitive value of config hidden from its message.
+func (r *Redactor) RedactError(err error, config map[string]string) error {
+	if err == nil {
+		return nil
+	}
+	msg := r.RedactString(err.Error(), config)
+	if msg == err.Error() {
+		return err
+	}
+	return errors.New(msg)
+}
+
+// ConfigChange is a single difference between two connector configurations.
+type ConfigChange struct {
+	Key string
+	// Old is empty when the key was added, New is empty when the key was removed.
+	Old     string
+	New     string
+	Added   bool
+	Removed bool
+}
+
+// String renders the change as a diff line.
+func (c ConfigChange) String() string {
+	switch {
+	case c.Added:
+		return fmt.Sprintf("+ %s: %s", c.Key, c.New)
+	case c.Removed:
+		return fmt.Sprintf("- %s: %s", c.Key, c.Old)
+	default:
+		return fmt.Sprintf("~ %s: %s -> %s", c.Key, c.Old, c.New)
+	}
+}
+
+// Diff returns the changes turning from into to, sorted by key, with sensitive values
+// redacted. A changed secret is reported without revealing either value.
+func (r *Redactor) Diff(from map[string]string, to map[string]string) []ConfigChange {
+	keys := map[string]bool{}
+	for k := range from {
+		keys[k] = true
+	}
+	for k := range to {
+		keys[k] = true
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var changes []ConfigChange
+	for _, k := range sorted {
+		oldValue, inFrom := from[k]
+		newValue, inTo := to[k]
+		if inFrom && inTo && oldValue == newValue {
+			continue
+		}
+		if r.IsSensitive(k) {
+			if oldValue != "" {
+				oldValue = RedactedValue
+			}
+			if newValue != "" {
+				newValue = RedactedValue
+			}
+		}
+		changes = append(changes, ConfigChange{Key: k, Old: oldValue, New: newValue, Added: !inFrom, Removed: !inTo})
+	}
+	return changes
+}
+
+// String renders the connector with sensitive values redacted by DefaultRedactor, so
+// connectors can be logged safely.
+func (c Connector) String() string {
+	redacted := DefaultRedactor.RedactConfig(c.Config)
+	keys := make([]string, 0, len(redacted))
+	for k := range redacted {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+redacted[k])
+	}
+	return fmt.Sprintf("{name=%s config={%s}}", c.Name, strings.Join(pairs, ", "))
+}
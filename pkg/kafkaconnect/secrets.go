@@ -0,0 +1,182 @@
+package kafkaconnect
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// placeholderPattern matches Kafka Connect config provider references of the form
+// ${provider:[path:]key}, using the same rules as Kafka's ConfigTransformer.
+var placeholderPattern = regexp.MustCompile(`\$\{([^}]*?):(([^}]*?):)?([^}]*?)\}`)
+
+// ConfigProvider resolves the value of key at path, mirroring Kafka's ConfigProvider.
+type ConfigProvider interface {
+	Get(path string, key string) (string, error)
+}
+
+// ConfigProviderFunc adapts a function to a ConfigProvider.
+type ConfigProviderFunc func(path string, key string) (string, error)
+
+// Get ...
+func (f ConfigProviderFunc) Get(path string, key string) (string, error) {
+	return f(path, key)
+}
+
+// EnvConfigProvider resolves ${env:NAME} from environment variables.
+type EnvConfigProvider struct{}
+
+// Get ...
+func (EnvConfigProvider) Get(path string, key string) (string, error) {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return "", fmt.Errorf("Environment variable %s is not set", key)
+	}
+	return value, nil
+}
+
+// FileConfigProvider resolves ${file:/path/to/file.properties:key} from Java properties
+// files, like Kafka's FileConfigProvider.
+type FileConfigProvider struct{}
+
+// Get ...
+func (FileConfigProvider) Get(path string, key string) (string, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	properties := parseProperties(content)
+	value, ok := properties[key]
+	if !ok {
+		return "", fmt.Errorf("Key %s not found in %s", key, path)
+	}
+	return value, nil
+}
+
+// DirectoryConfigProvider resolves ${dir:/path/to/dir:file} to the content of a file in a
+// directory, like Kafka's DirectoryConfigProvider. This matches Kubernetes secret volumes.
+type DirectoryConfigProvider struct{}
+
+// Get ...
+func (DirectoryConfigProvider) Get(path string, key string) (string, error) {
+	if strings.ContainsAny(key, `/\`) {
+		return "", fmt.Errorf("Invalid file name %s", key)
+	}
+	content, err := ioutil.ReadFile(filepath.Join(path, key))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+func parseProperties(content []byte) map[string]string {
+	properties := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		i := strings.IndexAny(line, "=:")
+		if i < 0 {
+			properties[line] = ""
+			continue
+		}
+		properties[strings.TrimSpace(line[:i])] = strings.TrimSpace(line[i+1:])
+	}
+	return properties
+}
+
+// SecretResolver substitutes config provider references in connector configurations
+// before they are sent to Kafka Connect. References to providers marked as passthrough
+// are left unchanged so the worker's own ConfigProviders can resolve them.
+type SecretResolver struct {
+	providers   map[string]ConfigProvider
+	passthrough map[string]bool
+}
+
+// NewSecretResolver returns a resolver with the env, file and dir providers registered.
+func NewSecretResolver() *SecretResolver {
+	return &SecretResolver{
+		providers: map[string]ConfigProvider{
+			"env":  EnvConfigProvider{},
+			"file": FileConfigProvider{},
+			"dir":  DirectoryConfigProvider{},
+		},
+		passthrough: map[string]bool{},
+	}
+}
+
+// Register adds or replaces the provider used for ${name:...} references.
+func (r *SecretResolver) Register(name string, provider ConfigProvider) {
+	r.providers[name] = provider
+}
+
+// Passthrough leaves references to the given providers unchanged.
+func (r *SecretResolver) Passthrough(providers ...string) {
+	for _, p := range providers {
+		r.passthrough[p] = true
+	}
+}
+
+// Resolve returns a copy of connector with every reference substituted. The keys whose
+// value changed are returned so callers can avoid exposing them. Errors never contain
+// resolved values.
+func (r *SecretResolver) Resolve(connector Connector) (Connector, []string, error) {
+	resolved := Connector{Name: connector.Name, InitialState: connector.InitialState}
+	if connector.Config == nil {
+		return resolved, nil, nil
+	}
+
+	var changed []string
+	resolved.Config = make(map[string]string, len(connector.Config))
+	for key, value := range connector.Config {
+		v, err := r.resolveValue(key, value)
+		if err != nil {
+			return Connector{}, nil, err
+		}
+		if v != value {
+			changed = append(changed, key)
+		}
+		resolved.Config[key] = v
+	}
+	return resolved, changed, nil
+}
+
+// ResolveValue substitutes the references in a single value. The key is only used in
+// error messages.
+func (r *SecretResolver) ResolveValue(key string, value string) (string, error) {
+	return r.resolveValue(key, value)
+}
+
+func (r *SecretResolver) resolveValue(key string, value string) (string, error) {
+	var resolveErr error
+	result := placeholderPattern.ReplaceAllStringFunc(value, func(ref string) string {
+		if resolveErr != nil {
+			return ref
+		}
+		m := placeholderPattern.FindStringSubmatch(ref)
+		provider, path, k := m[1], m[3], m[4]
+
+		if r.passthrough[provider] {
+			return ref
+		}
+		p, ok := r.providers[provider]
+		if !ok {
+			resolveErr = fmt.Errorf("Unknown config provider '%s' referenced by '%s'", provider, key)
+			return ref
+		}
+		v, err := p.Get(path, k)
+		if err != nil {
+			resolveErr = fmt.Errorf("Failed to resolve '%s' with config provider '%s': %s", key, provider, err.Error())
+			return ref
+		}
+		return v
+	})
+	return result, resolveErr
+}
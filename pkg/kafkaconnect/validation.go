@@ -0,0 +1,222 @@
+package kafkaconnect
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/walmartdigital/go-kaya/pkg/validator"
+)
+
+// ValidationMode selects how Create, Update and Apply validate connector configs before
+// sending them.
+type ValidationMode string
+
+// Validation modes.
+const (
+	// ValidationOff sends configs as they are. This is the default of NewClient.
+	ValidationOff ValidationMode = "off"
+	// ValidationLocal checks configs with a validator.Validator. This is the default of New.
+	ValidationLocal ValidationMode = "local"
+	// ValidationRemote asks the worker through PUT /connector-plugins/{class}/config/validate.
+	ValidationRemote ValidationMode = "remote"
+	// ValidationBoth runs the local validation, then the remote one if the config passed.
+	ValidationBoth ValidationMode = "both"
+)
+
+// ParseValidationMode returns the mode called s.
+func ParseValidationMode(s string) (ValidationMode, error) {
+	switch m := ValidationMode(s); m {
+	case ValidationOff, ValidationLocal, ValidationRemote, ValidationBoth:
+		return m, nil
+	}
+	return "", fmt.Errorf("Unknown validation mode %q, expected off, local, remote or both", s)
+}
+
+func (m ValidationMode) local() bool {
+	return m == ValidationLocal || m == ValidationBoth
+}
+
+func (m ValidationMode) remote() bool {
+	return m == ValidationRemote || m == ValidationBoth
+}
+
+// ValidationError is returned with a Response whose Result is "invalid" when a connector
+// config fails validation, locally, through the worker's validate endpoint or when the
+// worker rejects a write with a 400.
+type ValidationError struct {
+	Report *validator.Report
+}
+
+func (e *ValidationError) Error() string {
+	return e.Report.Error()
+}
+
+// configInfos is the response of the validate endpoint. Only the fields used to build
+// reports are decoded.
+type configInfos struct {
+	ErrorCount int `json:"error_count"`
+	Configs    []struct {
+		Value struct {
+			Name   string   `json:"name"`
+			Value  *string  `json:"value"`
+			Errors []string `json:"errors"`
+		} `json:"value"`
+	} `json:"configs"`
+}
+
+func (kcc Client) validationMode() ValidationMode {
+	if kcc.validation == "" {
+		return ValidationOff
+	}
+	return kcc.validation
+}
+
+// withName returns the config of connector along with its name, as validated by Kafka
+// Connect.
+func withName(connector Connector) map[string]string {
+	config := make(map[string]string, len(connector.Config)+1)
+	for k, v := range connector.Config {
+		config[k] = v
+	}
+	config["name"] = connector.Name
+	return config
+}
+
+// Validate checks the config of connector according to the validation mode of the client,
+// or locally when validation is off. The returned payload is a *validator.Report, and the
+// result is "invalid" when the report has violations.
+func (kcc Client) Validate(connector Connector) (*Response, error) {
+	mode := kcc.validationMode()
+	if mode == ValidationOff {
+		mode = ValidationLocal
+	}
+	report, err := kcc.validate(connector, mode)
+	if err != nil {
+		return &Response{Result: "error"}, err
+	}
+	response := &Response{Result: "success", Payload: report}
+	if !report.Valid() {
+		response.Result = "invalid"
+	}
+	return response, nil
+}
+
+// check validates connector before a write according to the validation mode of the client.
+// It returns the response and error to give back when the connector must not be sent.
+func (kcc Client) check(connector Connector) (*Response, error) {
+	report, err := kcc.validate(connector, kcc.validationMode())
+	if err != nil {
+		return &Response{Result: "error"}, err
+	}
+	if !report.Valid() {
+		return &Response{Result: "invalid"}, &ValidationError{Report: report}
+	}
+	return nil, nil
+}
+
+func (kcc Client) validate(connector Connector, mode ValidationMode) (*validator.Report, error) {
+	report := &validator.Report{Connector: connector.Name}
+	if mode.local() {
+		report = kcc.checker.Validate(withName(connector))
+		if !report.Valid() {
+			return report, nil
+		}
+	}
+	if mode.remote() {
+		return kcc.validateRemote(connector)
+	}
+	return report, nil
+}
+
+// validateRemote asks the worker to validate the config of connector, with secrets
+// resolved as they would be sent.
+func (kcc Client) validateRemote(connector Connector) (*validator.Report, error) {
+	resolved, secretKeys, err := kcc.resolveSecrets(connector)
+	if err != nil {
+		return nil, err
+	}
+	config := withName(resolved)
+	class := config["connector.class"]
+	if class == "" {
+		report := &validator.Report{Connector: connector.Name}
+		report.Violations = append(report.Violations, validator.Violation{Key: "connector.class", Rule: "required", Message: "Missing required configuration 'connector.class'"})
+		return report, nil
+	}
+
+	configBytes, err := json.Marshal(config)
+	if err != nil {
+		return nil, errors.New("Failed to serialize connector configuration")
+	}
+	status, body, err := kcc.httpClient.Put("/connector-plugins/"+url.PathEscape(class)+"/config/validate", configBytes)
+	kcc.logResult("Validate", connector.Name, status, err)
+	if err != nil {
+		return nil, fmt.Errorf("Error executing Validate on Kafka Connect: %s", err.Error())
+	}
+
+	switch status {
+	case 200:
+		var infos configInfos
+		if err := json.Unmarshal(*body, &infos); err != nil {
+			return nil, errors.New("Failed to deserialize Kafka Connect response")
+		}
+		return kcc.remoteReport(connector.Name, infos, config, secretKeys), nil
+	case 400:
+		return kcc.rejectedReport(connector.Name, body, config, secretKeys), nil
+	default:
+		_, err := HandleNonOKResponse(status, body)
+		return nil, kcc.secretRedactor(secretKeys).RedactError(err, config)
+	}
+}
+
+func (kcc Client) remoteReport(name string, infos configInfos, config map[string]string, secretKeys []string) *validator.Report {
+	redactor := kcc.secretRedactor(secretKeys)
+	report := &validator.Report{Connector: name}
+	for _, c := range infos.Configs {
+		for _, msg := range c.Value.Errors {
+			v := validator.Violation{
+				Key:     c.Value.Name,
+				Rule:    "remote",
+				Message: redactor.RedactString(msg, config),
+			}
+			if c.Value.Value != nil {
+				v.Value = *c.Value.Value
+				if redactor.IsSensitive(v.Key) && v.Value != "" {
+					v.Value = RedactedValue
+				}
+			}
+			report.Violations = append(report.Violations, v)
+		}
+	}
+	return report
+}
+
+// rejectedReport turns the body of a 400 response into a report.
+func (kcc Client) rejectedReport(name string, body *[]byte, config map[string]string, secretKeys []string) *validator.Report {
+	var kcError Error
+	if body != nil {
+		_ = json.Unmarshal(*body, &kcError)
+	}
+	message := kcError.Message
+	if message == "" {
+		message = "Rejected by Kafka Connect"
+	}
+	report := &validator.Report{Connector: name}
+	report.Violations = append(report.Violations, validator.Violation{
+		Rule:    "worker",
+		Message: kcc.secretRedactor(secretKeys).RedactString(message, config),
+	})
+	return report
+}
+
+// handleWriteResponse manages non successful responses to Create and Update. A 400 means
+// Kafka Connect rejected the config, which is returned as a ValidationError whatever the
+// validation mode.
+func (kcc Client) handleWriteResponse(name string, status int, body *[]byte, config map[string]string, secretKeys []string) (*Response, error) {
+	if status == 400 {
+		return &Response{Result: "invalid"}, &ValidationError{Report: kcc.rejectedReport(name, body, config, secretKeys)}
+	}
+	response, err := HandleNonOKResponse(status, body)
+	return response, kcc.secretRedactor(secretKeys).RedactError(err, config)
+}
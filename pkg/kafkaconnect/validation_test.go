@@ -0,0 +1,176 @@
+package kafkaconnect_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/walmartdigital/go-kaya/pkg/kafkaconnect"
+	"github.com/walmartdigital/go-kaya/pkg/kafkaconnect/kafkaconnecttest"
+	"github.com/walmartdigital/go-kaya/pkg/validator"
+)
+
+var _ = Describe("Validation before writes", func() {
+	var (
+		server    *kafkaconnecttest.Server
+		connector kafkaconnect.Connector
+	)
+
+	BeforeEach(func() {
+		server = kafkaconnecttest.NewServer()
+		connector = kafkaconnect.Connector{
+			Name: "logging",
+			Config: map[string]string{
+				"connector.class":     "io.confluent.connect.elasticsearch.ElasticsearchSinkConnector",
+				"topics":              "logs",
+				"connection.url":      "http://elasticsearch:9200",
+				"connection.password": "s3cr3t",
+			},
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	newClient := func(opts ...kafkaconnect.Option) *kafkaconnect.Client {
+		kcc, err := kafkaconnect.New(server.URL, opts...)
+		Expect(err).To(BeNil())
+		return kcc
+	}
+
+	It("should validate locally", func() {
+		kcc := newClient(kafkaconnect.WithValidation(kafkaconnect.ValidationLocal),
+			kafkaconnect.WithRedactor(kafkaconnect.NewRedactor("*password*", "key.ignore")))
+		connector.Config["tasks.max"] = "0"
+		connector.Config["key.ignore"] = "s3cr3t"
+		delete(connector.Config, "connection.url")
+
+		resp, err := kcc.Create(connector)
+		Expect(resp.Result).To(Equal("invalid"))
+		validationError, ok := err.(*kafkaconnect.ValidationError)
+		Expect(ok).To(BeTrue())
+		Expect(validationError.Report.Violations).To(HaveLen(3))
+		Expect(validationError.Report.ByKey("key.ignore")[0].Value).To(Equal(kafkaconnect.RedactedValue))
+
+		resp, err = kcc.Update(connector)
+		Expect(resp.Result).To(Equal("invalid"))
+		Expect(err).To(BeAssignableToTypeOf(&kafkaconnect.ValidationError{}))
+
+		resp, err = kcc.Apply(connector)
+		Expect(resp.Result).To(Equal("invalid"))
+		Expect(err).To(BeAssignableToTypeOf(&kafkaconnect.ValidationError{}))
+		Expect(server.Requests()).To(BeEmpty())
+	})
+
+	It("should validate locally and report configs rejected by the worker by default", func() {
+		kcc, err := kafkaconnect.New(server.URL)
+		Expect(err).To(BeNil())
+		delete(connector.Config, "connection.url")
+
+		resp, err := kcc.Create(connector)
+		Expect(resp.Result).To(Equal("invalid"))
+		Expect(err).To(MatchError(ContainSubstring("Missing required configuration 'connection.url'")))
+		Expect(server.Requests()).To(BeEmpty())
+
+		connector.Config["connection.url"] = "http://elasticsearch:9200"
+		connector.Config["connector.class"] = "com.example.UnknownConnector"
+		resp, err = kcc.Create(connector)
+		Expect(resp.Result).To(Equal("invalid"))
+		Expect(err).To(BeAssignableToTypeOf(&kafkaconnect.ValidationError{}))
+		Expect(err.(*kafkaconnect.ValidationError).Report.Violations[0].Rule).To(Equal("worker"))
+		Expect(server.Requests()).To(Equal([]string{"POST /connectors"}))
+	})
+
+	It("should send configs unchecked when validation is off", func() {
+		kcc := newClient(kafkaconnect.WithValidation(kafkaconnect.ValidationOff))
+		delete(connector.Config, "connection.url")
+
+		resp, err := kcc.Create(connector)
+		Expect(err).To(BeNil())
+		Expect(resp.Result).To(Equal("success"))
+	})
+
+	It("should validate through the worker", func() {
+		kcc := newClient(kafkaconnect.WithValidation(kafkaconnect.ValidationRemote))
+		connector.Config["topics.regex"] = "logs-.*"
+
+		resp, err := kcc.Create(connector)
+		Expect(resp.Result).To(Equal("invalid"))
+		Expect(err).To(BeAssignableToTypeOf(&kafkaconnect.ValidationError{}))
+		report := err.(*kafkaconnect.ValidationError).Report
+		Expect(report.Violations).To(ConsistOf(
+			validator.Violation{Key: "topics", Rule: "remote", Value: "logs", Message: "Must configure one of topics or topics.regex, but not both"},
+			validator.Violation{Key: "topics.regex", Rule: "remote", Value: "logs-.*", Message: "Must configure one of topics or topics.regex, but not both"},
+		))
+		Expect(server.Requests()).To(Equal([]string{
+			"PUT /connector-plugins/io.confluent.connect.elasticsearch.ElasticsearchSinkConnector/config/validate",
+		}))
+
+		delete(connector.Config, "topics.regex")
+		resp, err = kcc.Create(connector)
+		Expect(err).To(BeNil())
+		Expect(resp.Result).To(Equal("success"))
+	})
+
+	It("should only ask the worker once the config is valid locally", func() {
+		kcc := newClient(kafkaconnect.WithValidation(kafkaconnect.ValidationBoth))
+		connector.Config["tasks.max"] = "none"
+
+		_, err := kcc.Apply(connector)
+		Expect(err).To(MatchError(ContainSubstring("Invalid value for 'tasks.max': must be an int")))
+		Expect(server.Requests()).To(BeEmpty())
+
+		connector.Config["tasks.max"] = "2"
+		resp, err := kcc.Apply(connector)
+		Expect(err).To(BeNil())
+		Expect(resp.Result).To(Equal("success"))
+		Expect(server.Requests()[0]).To(HavePrefix("PUT /connector-plugins/"))
+	})
+
+	It("should report configs rejected by the worker", func() {
+		connector.Config["connector.class"] = "com.example.UnknownConnector"
+		kcc := newClient(kafkaconnect.WithValidation(kafkaconnect.ValidationOff))
+		resp, err := kcc.Create(connector)
+		Expect(resp.Result).To(Equal("invalid"))
+		Expect(err).To(BeAssignableToTypeOf(&kafkaconnect.ValidationError{}))
+		violations := err.(*kafkaconnect.ValidationError).Report.Violations
+		Expect(violations).To(HaveLen(1))
+		Expect(violations[0].Rule).To(Equal("worker"))
+		Expect(violations[0].Message).To(ContainSubstring("Failed to find any class that implements Connector"))
+	})
+
+	It("should validate on demand", func() {
+		kcc := newClient(kafkaconnect.WithValidation(kafkaconnect.ValidationOff))
+		resp, err := kcc.Validate(connector)
+		Expect(err).To(BeNil())
+		Expect(resp.Result).To(Equal("success"))
+		Expect(resp.Payload.(*validator.Report).Valid()).To(BeTrue())
+
+		connector.Config["tasks.max"] = "0"
+		resp, err = kcc.Validate(connector)
+		Expect(err).To(BeNil())
+		Expect(resp.Result).To(Equal("invalid"))
+		Expect(resp.Payload.(*validator.Report).ByKey("tasks.max")).To(HaveLen(1))
+	})
+
+	It("should use a custom validator", func() {
+		registry := validator.NewRegistry()
+		registry.Register(validator.Schema{
+			Class:  "io.confluent.connect.elasticsearch.ElasticsearchSinkConnector",
+			Fields: map[string]validator.Field{"index.prefix": {Required: true}},
+		})
+		kcc := newClient(kafkaconnect.WithValidation(kafkaconnect.ValidationLocal),
+			kafkaconnect.WithValidator(validator.New(validator.WithRegistry(registry))))
+
+		_, err := kcc.Create(connector)
+		Expect(err).To(MatchError("Invalid config for connector logging: Missing required configuration 'index.prefix'"))
+	})
+
+	It("should parse validation modes", func() {
+		mode, err := kafkaconnect.ParseValidationMode("both")
+		Expect(err).To(BeNil())
+		Expect(mode).To(Equal(kafkaconnect.ValidationBoth))
+
+		_, err = kafkaconnect.ParseValidationMode("strict")
+		Expect(err).To(MatchError(`Unknown validation mode "strict", expected off, local, remote or both`))
+	})
+})
@@ -0,0 +1,530 @@
+// Package manifest reads connector definitions from JSON and YAML files.
+//
+// A file holds one or more documents: YAML documents separated by ---, a stream of JSON
+// objects or a JSON array. Each document is either a plain connector:
+//
+//	name: logging
+//	config:
+//	  connector.class: io.confluent.connect.elasticsearch.ElasticsearchSinkConnector
+//	  tasks.max: 2
+//	  topics: [dumblogger-logs, _ims.logs]
+//
+// or a Kubernetes-style KafkaConnector resource, as used by Strimzi:
+//
+//	apiVersion: kafka.strimzi.io/v1beta2
+//	kind: KafkaConnector
+//	metadata:
+//	  name: logging
+//	spec:
+//	  class: io.confluent.connect.elasticsearch.ElasticsearchSinkConnector
+//	  tasksMax: 2
+//	  config:
+//	    topics: dumblogger-logs,_ims.logs
+//
+// Config values may be strings, numbers, booleans or lists, which are joined with commas.
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/walmartdigital/go-kaya/pkg/kafkaconnect"
+	"gopkg.in/yaml.v2"
+)
+
+// KindKafkaConnector is the kind of Kubernetes-style connector documents.
+const KindKafkaConnector = "KafkaConnector"
+
+// Extensions are the file extensions read when loading a directory.
+var Extensions = []string{".json", ".yaml", ".yml"}
+
+// Document is a connector definition read from a file.
+type Document struct {
+	Connector kafkaconnect.Connector
+	// Unset lists the config keys explicitly set to null, which overlays use to remove
+	// settings.
+	Unset []string
+	File  string
+	// Line is the line at which the document starts.
+	Line int
+}
+
+// Error is a problem found in a manifest, located by file and line.
+type Error struct {
+	File    string
+	Line    int
+	Message string
+}
+
+// Error ...
+func (e *Error) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Message)
+	}
+	return fmt.Sprintf("%s: %s", e.File, e.Message)
+}
+
+// Files expands paths into the manifest files they contain. Directories are walked
+// recursively and only files with one of the Extensions are kept, in lexical order.
+func Files(paths ...string) ([]string, error) {
+	var files []string
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, p)
+			continue
+		}
+		err = filepath.Walk(p, func(file string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() && hasExtension(file) {
+				files = append(files, file)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+func hasExtension(file string) bool {
+	ext := strings.ToLower(filepath.Ext(file))
+	for _, e := range Extensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Load reads every document of the given files and directories.
+func Load(paths ...string) ([]Document, error) {
+	files, err := Files(paths...)
+	if err != nil {
+		return nil, err
+	}
+	var docs []Document
+	for _, file := range files {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		d, err := Parse(file, content)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, d...)
+	}
+	return docs, nil
+}
+
+// Connectors returns the connectors of docs, failing if a name is defined more than once.
+func Connectors(docs []Document) ([]kafkaconnect.Connector, error) {
+	seen := map[string]Document{}
+	connectors := make([]kafkaconnect.Connector, 0, len(docs))
+	for _, d := range docs {
+		if first, ok := seen[d.Connector.Name]; ok {
+			return nil, &Error{File: d.File, Line: d.Line, Message: fmt.Sprintf("Connector %s is already defined at %s:%d", d.Connector.Name, first.File, first.Line)}
+		}
+		seen[d.Connector.Name] = d
+		connectors = append(connectors, d.Connector)
+	}
+	return connectors, nil
+}
+
+// Parse reads the documents of a single file. The file name is only used in errors.
+func Parse(file string, content []byte) ([]Document, error) {
+	trimmed := bytes.TrimSpace(content)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		return parseJSON(file, content)
+	}
+	return parseYAML(file, content)
+}
+
+// chunk is the raw content of one document.
+type chunk struct {
+	value interface{}
+	line  int
+	text  []byte
+}
+
+func parseJSON(file string, content []byte) ([]Document, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+
+	var chunks []chunk
+	for {
+		start := skipSeparators(content, int(decoder.InputOffset()))
+		if start < len(content) && content[start] == '[' {
+			// Elements of a top level array are decoded one by one to locate each of them.
+			elements, err := parseJSONArray(file, content, decoder)
+			if err != nil {
+				return nil, err
+			}
+			chunks = append(chunks, elements...)
+			continue
+		}
+		c, err := decodeJSON(file, content, decoder)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, c)
+	}
+	return documents(file, chunks)
+}
+
+func parseJSONArray(file string, content []byte, decoder *json.Decoder) ([]chunk, error) {
+	start := skipSeparators(content, int(decoder.InputOffset()))
+	if _, err := decoder.Token(); err != nil {
+		return nil, &Error{File: file, Line: jsonErrorLine(content, err, start), Message: err.Error()}
+	}
+	var chunks []chunk
+	for decoder.More() {
+		c, err := decodeJSON(file, content, decoder)
+		if err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, c)
+	}
+	start = skipSeparators(content, int(decoder.InputOffset()))
+	if _, err := decoder.Token(); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, &Error{File: file, Line: jsonErrorLine(content, err, start), Message: err.Error()}
+	}
+	return chunks, nil
+}
+
+// decodeJSON decodes the next value of decoder into a chunk, or returns io.EOF at the end
+// of the content.
+func decodeJSON(file string, content []byte, decoder *json.Decoder) (chunk, error) {
+	start := skipSeparators(content, int(decoder.InputOffset()))
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		if err == io.EOF {
+			return chunk{}, err
+		}
+		return chunk{}, &Error{File: file, Line: jsonErrorLine(content, err, start), Message: err.Error()}
+	}
+	end := int(decoder.InputOffset())
+	return chunk{value: value, line: lineAt(content, start), text: content[start:end]}, nil
+}
+
+func jsonErrorLine(content []byte, err error, start int) int {
+	if e, ok := err.(*json.SyntaxError); ok {
+		return lineAt(content, int(e.Offset))
+	}
+	return lineAt(content, start)
+}
+
+var (
+	separatorPattern = regexp.MustCompile(`^---(\s|$)`)
+	yamlLinePattern  = regexp.MustCompile(`line (\d+): `)
+)
+
+func parseYAML(file string, content []byte) ([]Document, error) {
+	var chunks []chunk
+	lines := strings.SplitAfter(string(content), "\n")
+	start := 0
+	flush := func(end int) error {
+		text := []byte(strings.Join(lines[start:end], ""))
+		var value interface{}
+		if err := yaml.Unmarshal(text, &value); err != nil {
+			return &Error{File: file, Line: start + yamlErrorLine(err), Message: yamlLinePattern.ReplaceAllString(err.Error(), "")}
+		}
+		if value != nil {
+			chunks = append(chunks, chunk{value: normalize(value), line: start + firstContentLine(text), text: text})
+		}
+		return nil
+	}
+	for i, line := range lines {
+		if separatorPattern.MatchString(line) {
+			if err := flush(i); err != nil {
+				return nil, err
+			}
+			start = i + 1
+		}
+	}
+	if err := flush(len(lines)); err != nil {
+		return nil, err
+	}
+	return documents(file, chunks)
+}
+
+// yamlErrorLine extracts the 1-based line reported by the YAML decoder.
+func yamlErrorLine(err error) int {
+	m := yamlLinePattern.FindStringSubmatch(err.Error())
+	if m == nil {
+		return 1
+	}
+	line, _ := strconv.Atoi(m[1])
+	return line
+}
+
+// firstContentLine returns the 1-based line of the first line that is not blank or a comment.
+func firstContentLine(text []byte) int {
+	for i, line := range strings.Split(string(text), "\n") {
+		l := strings.TrimSpace(line)
+		if l != "" && !strings.HasPrefix(l, "#") {
+			return i + 1
+		}
+	}
+	return 1
+}
+
+func skipSeparators(content []byte, offset int) int {
+	for offset < len(content) && strings.ContainsRune(" \t\r\n,", rune(content[offset])) {
+		offset++
+	}
+	return offset
+}
+
+// lineAt returns the 1-based line of offset in content.
+func lineAt(content []byte, offset int) int {
+	if offset > len(content) {
+		offset = len(content)
+	}
+	return bytes.Count(content[:offset], []byte("\n")) + 1
+}
+
+// keyLine locates key within a document, falling back to the start of the document.
+func (c chunk) keyLine(key string) int {
+	for i, line := range strings.Split(string(c.text), "\n") {
+		l := strings.TrimSpace(line)
+		if strings.HasPrefix(l, key+":") || strings.HasPrefix(l, `"`+key+`"`) || strings.HasPrefix(l, `'`+key+`'`) {
+			return c.line + i - (firstContentLine(c.text) - 1)
+		}
+	}
+	return c.line
+}
+
+func documents(file string, chunks []chunk) ([]Document, error) {
+	docs := make([]Document, 0, len(chunks))
+	for _, c := range chunks {
+		d, err := c.document()
+		if err != nil {
+			if e, ok := err.(*Error); ok {
+				e.File = file
+				return nil, e
+			}
+			return nil, &Error{File: file, Line: c.line, Message: err.Error()}
+		}
+		d.File = file
+		d.Line = c.line
+		docs = append(docs, *d)
+	}
+	return docs, nil
+}
+
+func (c chunk) document() (*Document, error) {
+	fields, ok := c.value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Expected a connector definition, got %s", kind(c.value))
+	}
+
+	if k, ok := fields["kind"]; ok {
+		if k != KindKafkaConnector {
+			return nil, &Error{Line: c.keyLine("kind"), Message: fmt.Sprintf("Unsupported kind '%v', expected %s", k, KindKafkaConnector)}
+		}
+		return c.envelope(fields)
+	}
+
+	if err := c.checkFields(fields, "name", "config"); err != nil {
+		return nil, err
+	}
+	d := &Document{Connector: kafkaconnect.Connector{Config: map[string]string{}}}
+	if err := c.setName(d, fields["name"], "name"); err != nil {
+		return nil, err
+	}
+	if err := c.setConfig(d, fields["config"], "config"); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (c chunk) envelope(fields map[string]interface{}) (*Document, error) {
+	if err := c.checkFields(fields, "apiVersion", "kind", "metadata", "spec"); err != nil {
+		return nil, err
+	}
+	d := &Document{Connector: kafkaconnect.Connector{Config: map[string]string{}}}
+
+	metadata, ok := fields["metadata"].(map[string]interface{})
+	if !ok {
+		return nil, &Error{Line: c.keyLine("metadata"), Message: "A KafkaConnector requires metadata.name"}
+	}
+	if err := c.setName(d, metadata["name"], "name"); err != nil {
+		return nil, err
+	}
+
+	spec, ok := fields["spec"].(map[string]interface{})
+	if !ok {
+		return nil, &Error{Line: c.keyLine("spec"), Message: "A KafkaConnector requires a spec"}
+	}
+	if err := c.setConfig(d, spec["config"], "config"); err != nil {
+		return nil, err
+	}
+	for field, key := range map[string]string{"class": "connector.class", "tasksMax": "tasks.max"} {
+		v, ok := spec[field]
+		if !ok {
+			continue
+		}
+		s, set, err := configValue(v)
+		if err != nil || !set {
+			return nil, &Error{Line: c.keyLine(field), Message: fmt.Sprintf("Invalid value for '%s': expected a string or number", field)}
+		}
+		d.Connector.Config[key] = s
+	}
+	return d, nil
+}
+
+func (c chunk) checkFields(fields map[string]interface{}, allowed ...string) error {
+	var unknown []string
+	for k := range fields {
+		found := false
+		for _, a := range allowed {
+			found = found || k == a
+		}
+		if !found {
+			unknown = append(unknown, k)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+	return &Error{Line: c.keyLine(unknown[0]), Message: fmt.Sprintf("Unknown field '%s', expected one of %s", unknown[0], strings.Join(allowed, ", "))}
+}
+
+func (c chunk) setName(d *Document, value interface{}, field string) error {
+	if value == nil {
+		return nil
+	}
+	name, ok := value.(string)
+	if !ok {
+		return &Error{Line: c.keyLine(field), Message: fmt.Sprintf("Invalid value for '%s': expected a string, got %s", field, kind(value))}
+	}
+	d.Connector.Name = name
+	return nil
+}
+
+func (c chunk) setConfig(d *Document, value interface{}, field string) error {
+	if value == nil {
+		return nil
+	}
+	config, ok := value.(map[string]interface{})
+	if !ok {
+		return &Error{Line: c.keyLine(field), Message: fmt.Sprintf("Invalid value for '%s': expected a map, got %s", field, kind(value))}
+	}
+	keys := make([]string, 0, len(config))
+	for k := range config {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		s, set, err := configValue(config[k])
+		if err != nil {
+			return &Error{Line: c.keyLine(k), Message: fmt.Sprintf("Invalid value for '%s': %s", k, err.Error())}
+		}
+		if !set {
+			d.Unset = append(d.Unset, k)
+			continue
+		}
+		d.Connector.Config[k] = s
+	}
+	return nil
+}
+
+// configValue converts a decoded config value into the string Kafka Connect expects. It
+// returns false for null.
+func configValue(v interface{}) (string, bool, error) {
+	if v == nil {
+		return "", false, nil
+	}
+	if list, ok := v.([]interface{}); ok {
+		items := make([]string, len(list))
+		for i, item := range list {
+			s, err := scalar(item)
+			if err != nil {
+				return "", false, err
+			}
+			items[i] = s
+		}
+		return strings.Join(items, ","), true, nil
+	}
+	s, err := scalar(v)
+	return s, err == nil, err
+}
+
+func scalar(v interface{}) (string, error) {
+	switch s := v.(type) {
+	case string:
+		return s, nil
+	case json.Number:
+		return s.String(), nil
+	case int:
+		return strconv.Itoa(s), nil
+	case int64:
+		return strconv.FormatInt(s, 10), nil
+	case uint64:
+		return strconv.FormatUint(s, 10), nil
+	case float64:
+		return strconv.FormatFloat(s, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(s), nil
+	default:
+		return "", fmt.Errorf("expected a string, number, boolean or list, got %s", kind(v))
+	}
+}
+
+func kind(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}:
+		return "an object"
+	case []interface{}:
+		return "a list"
+	case nil:
+		return "nothing"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// normalize converts the map[interface{}]interface{} produced by the YAML decoder into
+// map[string]interface{}.
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			m[fmt.Sprint(k)] = normalize(val)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(v))
+		for i, val := range v {
+			l[i] = normalize(val)
+		}
+		return l
+	default:
+		return value
+	}
+}
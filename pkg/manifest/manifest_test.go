@@ -0,0 +1,146 @@
+package manifest_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/walmartdigital/go-kaya/pkg/kafkaconnect"
+	"github.com/walmartdigital/go-kaya/pkg/manifest"
+)
+
+func TestAll(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Manifest")
+}
+
+func parseError(content string) *manifest.Error {
+	_, err := manifest.Parse("connector.yaml", []byte(content))
+	Expect(err).To(BeAssignableToTypeOf(&manifest.Error{}))
+	return err.(*manifest.Error)
+}
+
+var _ = Describe("Manifest", func() {
+	It("should load every document of a directory", func() {
+		docs, err := manifest.Load("testdata/connectors")
+		Expect(err).To(BeNil())
+		Expect(docs).To(HaveLen(4))
+
+		Expect(docs[0].File).To(Equal("testdata/connectors/logging.yaml"))
+		Expect(docs[0].Line).To(Equal(2))
+		Expect(docs[0].Connector).To(Equal(kafkaconnect.Connector{
+			Name: "logging",
+			Config: map[string]string{
+				"connector.class": "io.confluent.connect.elasticsearch.ElasticsearchSinkConnector",
+				"tasks.max":       "2",
+				"topics":          "dumblogger-logs,_ims.logs",
+				"key.ignore":      "true",
+			},
+		}))
+
+		Expect(docs[1].Line).To(Equal(11))
+		Expect(docs[1].Connector).To(Equal(kafkaconnect.Connector{
+			Name: "metrics",
+			Config: map[string]string{
+				"connector.class": "io.confluent.connect.elasticsearch.ElasticsearchSinkConnector",
+				"tasks.max":       "1",
+				"topics":          "metrics",
+				"batch.size":      "100",
+			},
+		}))
+
+		Expect(docs[2].File).To(Equal("testdata/connectors/nested/audit.json"))
+		Expect(docs[2].Connector.Name).To(Equal("audit"))
+		Expect(docs[3].Line).To(Equal(2))
+		Expect(docs[3].Connector.Name).To(Equal("audit-dlq"))
+
+		connectors, err := manifest.Connectors(docs)
+		Expect(err).To(BeNil())
+		Expect(connectors).To(HaveLen(4))
+	})
+
+	It("should read the JSON connector files of e2e", func() {
+		docs, err := manifest.Load("../../e2e/create_connector.json")
+		Expect(err).To(BeNil())
+		Expect(docs).To(HaveLen(1))
+		Expect(docs[0].Connector.Config["connection.password"]).To(Equal("${env:ES_PASSWORD}"))
+	})
+
+	It("should read JSON arrays", func() {
+		docs, err := manifest.Parse("connectors.json", []byte(`[{"name": "a"}, {"name": "b", "config": {"tasks.max": 3}}]`))
+		Expect(err).To(BeNil())
+		Expect(docs).To(HaveLen(2))
+		Expect(docs[1].Connector.Config).To(Equal(map[string]string{"tasks.max": "3"}))
+	})
+
+	It("should locate each element of a JSON array", func() {
+		content := "[\n  {\"name\": \"a\"},\n\n  {\n    \"name\": \"b\",\n    \"config\": {\"tasks.max\": 3}\n  }\n]\n"
+		docs, err := manifest.Parse("connectors.json", []byte(content))
+		Expect(err).To(BeNil())
+		Expect(docs).To(HaveLen(2))
+		Expect(docs[0].Line).To(Equal(2))
+		Expect(docs[1].Line).To(Equal(4))
+
+		_, err = manifest.Parse("connectors.json", []byte("[\n  {\"name\": \"a\"},\n  {\"name\": \"b\", \"tasks\": 1}\n]\n"))
+		Expect(err).NotTo(BeNil())
+		Expect(err.(*manifest.Error).Line).To(Equal(3))
+
+		_, err = manifest.Parse("connectors.json", []byte("[\n  {\"name\": \"a\"}\n"))
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should record config keys set to null", func() {
+		docs, err := manifest.Parse("overlay.yaml", []byte("config:\n  batch.size: null\n  topics: logs\n"))
+		Expect(err).To(BeNil())
+		Expect(docs[0].Unset).To(Equal([]string{"batch.size"}))
+		Expect(docs[0].Connector.Config).To(Equal(map[string]string{"topics": "logs"}))
+	})
+
+	It("should reject duplicate connector names", func() {
+		docs, err := manifest.Parse("connectors.yaml", []byte("name: a\n---\nname: a\n"))
+		Expect(err).To(BeNil())
+		_, err = manifest.Connectors(docs)
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(Equal("connectors.yaml:3: Connector a is already defined at connectors.yaml:1"))
+	})
+
+	It("should locate YAML syntax errors", func() {
+		err := parseError("name: a\n---\nname: b\nconfig:\n  topics: [a\n")
+		Expect(err.Line).To(BeNumerically(">=", 5))
+		Expect(err.Error()).To(HavePrefix("connector.yaml:"))
+	})
+
+	It("should locate JSON syntax errors", func() {
+		_, err := manifest.Parse("connector.json", []byte("{\n  \"name\": \"a\",\n  \"config\": {\n    \"topics\": \"a\",\n  }\n}\n"))
+		Expect(err).NotTo(BeNil())
+		Expect(err.(*manifest.Error).Line).To(Equal(5))
+	})
+
+	It("should locate invalid config values", func() {
+		err := parseError("name: a\nconfig:\n  topics: logs\n  transforms:\n    unwrap: x\n")
+		Expect(err.Line).To(Equal(4))
+		Expect(err.Message).To(ContainSubstring("Invalid value for 'transforms'"))
+	})
+
+	It("should reject unknown fields", func() {
+		err := parseError("name: a\nconfigs:\n  topics: logs\n")
+		Expect(err.Line).To(Equal(2))
+		Expect(err.Message).To(ContainSubstring("Unknown field 'configs'"))
+	})
+
+	It("should reject unsupported kinds", func() {
+		err := parseError("kind: Deployment\nmetadata:\n  name: a\n")
+		Expect(err.Line).To(Equal(1))
+		Expect(err.Message).To(ContainSubstring("Unsupported kind 'Deployment'"))
+	})
+})
+
+var _ = Describe("e2e manifests", func() {
+	It("should define the same connector in JSON and YAML", func() {
+		fromJSON, err := manifest.Load("../../e2e/create_connector.json")
+		Expect(err).To(BeNil())
+		fromYAML, err := manifest.Load("../../e2e/create_connector.yaml")
+		Expect(err).To(BeNil())
+		Expect(fromYAML[0].Connector).To(Equal(fromJSON[0].Connector))
+	})
+})
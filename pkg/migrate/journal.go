@@ -0,0 +1,92 @@
+package migrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/walmartdigital/go-kaya/pkg/kafkaconnect"
+)
+
+// Journal records the progress of a migration in a file, so that a migration that failed
+// half way can be run again and continue after its last completed step. It holds the
+// completed steps and the offsets read from the source cluster, but not the config of the
+// connector, which may contain secrets: a resumed migration reads it from the source again.
+type Journal struct {
+	path string
+
+	Connector string `json:"connector"`
+	// Offsets are nil when the offsets were not read or are not supported by the source.
+	Offsets   *kafkaconnect.Offsets `json:"offsets,omitempty"`
+	Completed []string              `json:"completed"`
+}
+
+// OpenJournal loads the journal at path, or returns an empty journal when the file does
+// not exist yet. Nothing is written until a step completes.
+func OpenJournal(path string) (*Journal, error) {
+	j := &Journal{path: path, Completed: []string{}}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return j, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read journal %s: %s", path, err.Error())
+	}
+	if err := json.Unmarshal(data, j); err != nil {
+		return nil, fmt.Errorf("Failed to parse journal %s: %s", path, err.Error())
+	}
+	return j, nil
+}
+
+// Path returns the file of the journal.
+func (j *Journal) Path() string {
+	return j.path
+}
+
+// Done tells whether step completed in an earlier or the current run.
+func (j *Journal) Done(step string) bool {
+	for _, s := range j.Completed {
+		if s == step {
+			return true
+		}
+	}
+	return false
+}
+
+// complete records step as completed and saves the journal.
+func (j *Journal) complete(step string) error {
+	if !j.Done(step) {
+		j.Completed = append(j.Completed, step)
+	}
+	return j.save()
+}
+
+// save replaces the journal file atomically, so that a crash leaves either the previous
+// or the new journal.
+func (j *Journal) save() error {
+	if j.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Failed to serialize journal: %s", err.Error())
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(j.path), filepath.Base(j.path)+".*")
+	if err != nil {
+		return fmt.Errorf("Failed to write journal %s: %s", j.path, err.Error())
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("Failed to write journal %s: %s", j.path, err.Error())
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("Failed to write journal %s: %s", j.path, err.Error())
+	}
+	if err := os.Rename(tmp.Name(), j.path); err != nil {
+		return fmt.Errorf("Failed to write journal %s: %s", j.path, err.Error())
+	}
+	return nil
+}
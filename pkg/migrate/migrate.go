@@ -0,0 +1,528 @@
+// Package migrate moves a connector from one Kafka Connect cluster to another:
+//
+//	m := migrate.New(source, target, migrate.WithStop(), migrate.WithJournal("orders.json"))
+//	result, err := m.Migrate("orders")
+//
+// A migration runs the steps below in order and checks the state of the connector on both
+// clusters after each of them:
+//
+//  1. read: read the config on the source, which must not exist on the target yet.
+//  2. stop-source: stop the connector on the source when WithStop is given.
+//  3. read-offsets: read the offsets on the source, skipped when the source does not
+//     support the offsets API.
+//  4. create-target: create the connector on the target, stopped when offsets are copied,
+//     which requires Kafka Connect 3.7 on the target.
+//  5. copy-offsets: set the offsets read from the source on the target.
+//  6. start-target: resume the connector on the target and wait for it to run.
+//  7. retire-source: delete or pause the connector on the source.
+//
+// With a journal, a failed migration can be run again and continues after the last step
+// it completed. The journal does not hold the connector config, which may contain secrets;
+// it is read from the source cluster again.
+package migrate
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/walmartdigital/go-kaya/pkg/kafkaconnect"
+)
+
+// Steps of a migration, in the order they run.
+const (
+	StepRead         = "read"
+	StepStopSource   = "stop-source"
+	StepReadOffsets  = "read-offsets"
+	StepCreateTarget = "create-target"
+	StepCopyOffsets  = "copy-offsets"
+	StepStartTarget  = "start-target"
+	StepRetireSource = "retire-source"
+)
+
+// Statuses of the steps of a Result.
+const (
+	// StatusDone is a step that ran in this migration.
+	StatusDone = "done"
+	// StatusSkipped is a step that did not apply to the connector or the clusters.
+	StatusSkipped = "skipped"
+	// StatusResumed is a step the journal records as completed by an earlier run.
+	StatusResumed = "resumed"
+	// StatusPlanned is a step that changes a cluster and was not run because of WithDryRun.
+	StatusPlanned = "planned"
+)
+
+// SourceAction is what happens to the connector on the source cluster once it runs on the
+// target.
+type SourceAction string
+
+const (
+	// DeleteSource deletes the connector from the source. This is the default.
+	DeleteSource SourceAction = "delete"
+	// PauseSource pauses the connector on the source, keeping it to roll back.
+	PauseSource SourceAction = "pause"
+)
+
+// ParseSourceAction returns the SourceAction named s.
+func ParseSourceAction(s string) (SourceAction, error) {
+	switch a := SourceAction(s); a {
+	case DeleteSource, PauseSource:
+		return a, nil
+	}
+	return "", fmt.Errorf("Unknown source action %q, expected %s or %s", s, DeleteSource, PauseSource)
+}
+
+// Step is the outcome of a step of a migration.
+type Step struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// Result lists the steps of a migration that ran, up to the one that failed.
+type Result struct {
+	Connector string `json:"connector"`
+	DryRun    bool   `json:"dryRun"`
+	Steps     []Step `json:"steps"`
+}
+
+// Option configures a Migrator.
+type Option func(*options)
+
+type options struct {
+	stop     bool
+	offsets  bool
+	action   SourceAction
+	dryRun   bool
+	journal  string
+	timeout  time.Duration
+	interval time.Duration
+}
+
+// WithStop stops the connector on the source before reading its offsets, so that they do
+// not move while the connector is migrated. Requires Kafka Connect 3.5 on the source.
+func WithStop() Option {
+	return func(o *options) {
+		o.stop = true
+	}
+}
+
+// WithoutOffsets creates the connector on the target without copying its offsets.
+func WithoutOffsets() Option {
+	return func(o *options) {
+		o.offsets = false
+	}
+}
+
+// WithSourceAction sets what happens to the connector on the source. Defaults to
+// DeleteSource.
+func WithSourceAction(action SourceAction) Option {
+	return func(o *options) {
+		o.action = action
+	}
+}
+
+// WithDryRun only runs the steps reading the clusters and reports the others as planned.
+// The journal is neither read nor written.
+func WithDryRun() Option {
+	return func(o *options) {
+		o.dryRun = true
+	}
+}
+
+// WithJournal records the completed steps in the file at path and skips the steps it
+// already records.
+func WithJournal(path string) Option {
+	return func(o *options) {
+		o.journal = path
+	}
+}
+
+// WithWait sets how long to wait for the connector to reach a state after a step, and how
+// often to poll its status. Defaults to one minute and one second.
+func WithWait(timeout time.Duration, interval time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+		o.interval = interval
+	}
+}
+
+// Client is what a Migrator needs of the clients of both clusters. kafkaconnect.Client
+// implements it.
+type Client interface {
+	kafkaconnect.KafkaConnectClient
+	kafkaconnect.LifecycleManager
+}
+
+// Migrator moves connectors from a source to a target cluster.
+type Migrator struct {
+	source Client
+	target Client
+	opts   options
+}
+
+// New returns a Migrator moving connectors from source to target.
+func New(source Client, target Client, opts ...Option) *Migrator {
+	o := options{
+		offsets:  true,
+		action:   DeleteSource,
+		timeout:  time.Minute,
+		interval: time.Second,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Migrator{source: source, target: target, opts: o}
+}
+
+// run is a migration in progress.
+type run struct {
+	*Migrator
+	name    string
+	journal *Journal
+	result  *Result
+	// config is the config read from the source, nil until read.
+	config map[string]string
+}
+
+// Migrate moves the connector name to the target cluster. The returned result lists the
+// steps that ran, also when an error is returned.
+func (m *Migrator) Migrate(name string) (*Result, error) {
+	r := &run{
+		Migrator: m,
+		name:     name,
+		journal:  &Journal{Connector: name, Completed: []string{}},
+		result:   &Result{Connector: name, DryRun: m.opts.dryRun, Steps: []Step{}},
+	}
+	if m.opts.journal != "" && !m.opts.dryRun {
+		j, err := OpenJournal(m.opts.journal)
+		if err != nil {
+			return r.result, err
+		}
+		if j.Connector != "" && j.Connector != name {
+			return r.result, fmt.Errorf("Journal %s records the migration of connector %s, not %s", j.Path(), j.Connector, name)
+		}
+		j.Connector = name
+		r.journal = j
+	}
+
+	steps := []struct {
+		name string
+		run  func() (string, string, error)
+	}{
+		{StepRead, r.read},
+		{StepStopSource, r.stopSource},
+		{StepReadOffsets, r.readOffsets},
+		{StepCreateTarget, r.createTarget},
+		{StepCopyOffsets, r.copyOffsets},
+		{StepStartTarget, r.startTarget},
+		{StepRetireSource, r.retireSource},
+	}
+	for _, s := range steps {
+		if r.journal.Done(s.name) {
+			r.add(s.name, StatusResumed, "")
+			continue
+		}
+		status, message, err := s.run()
+		if err != nil {
+			return r.result, fmt.Errorf("Step %s failed: %s", s.name, err.Error())
+		}
+		r.add(s.name, status, message)
+		if status != StatusPlanned && !m.opts.dryRun {
+			if err := r.journal.complete(s.name); err != nil {
+				return r.result, err
+			}
+		}
+	}
+	return r.result, nil
+}
+
+func (r *run) add(name string, status string, message string) {
+	r.result.Steps = append(r.result.Steps, Step{Name: name, Status: status, Message: message})
+}
+
+// copying tells whether offsets read from the source are to be set on the target.
+func (r *run) copying() bool {
+	return r.journal.Offsets != nil && len(r.journal.Offsets.Offsets) > 0
+}
+
+func (r *run) read() (string, string, error) {
+	config, err := r.sourceConfig()
+	if err != nil {
+		return "", "", err
+	}
+
+	source, err := status(r.source, r.name)
+	if err != nil {
+		return "", "", err
+	}
+	if source == nil {
+		return "", "", fmt.Errorf("Connector %s has no status on the source cluster", r.name)
+	}
+	target, err := status(r.target, r.name)
+	if err != nil {
+		return "", "", err
+	}
+	if target != nil {
+		return "", "", fmt.Errorf("Connector %s already exists on the target cluster", r.name)
+	}
+
+	return StatusDone, fmt.Sprintf("%d config keys, %s on the source cluster", len(config), source.Connector.State), nil
+}
+
+// sourceConfig returns the config read from the source, reading it again when the read
+// step completed in an earlier run. The source keeps the connector until the last step.
+func (r *run) sourceConfig() (map[string]string, error) {
+	if r.config != nil {
+		return r.config, nil
+	}
+	resp, err := r.source.Read(r.name)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read connector %s on the source cluster: %s", r.name, err.Error())
+	}
+	r.config, _ = resp.Payload.(map[string]string)
+	return r.config, nil
+}
+
+func (r *run) stopSource() (string, string, error) {
+	if !r.opts.stop {
+		return StatusSkipped, "the connector keeps running on the source cluster", nil
+	}
+	if r.opts.dryRun {
+		return StatusPlanned, "stop the connector on the source cluster", nil
+	}
+	if resp, err := r.source.Stop(r.name); err != nil {
+		if resp != nil && resp.Result == "notfound" {
+			return "", "", fmt.Errorf("Failed to stop connector %s, stopping requires Kafka Connect 3.5 on the source cluster: %s", r.name, err.Error())
+		}
+		return "", "", fmt.Errorf("Failed to stop connector %s: %s", r.name, err.Error())
+	}
+	if err := r.waitState(r.source, "source", kafkaconnect.StateStopped); err != nil {
+		return "", "", err
+	}
+	return StatusDone, "", nil
+}
+
+func (r *run) readOffsets() (string, string, error) {
+	if !r.opts.offsets {
+		return StatusSkipped, "offsets are not copied", nil
+	}
+	resp, err := r.source.GetOffsets(r.name)
+	if err != nil {
+		if resp != nil && resp.Result == "notfound" {
+			return StatusSkipped, "the source cluster does not support the offsets API", nil
+		}
+		return "", "", fmt.Errorf("Failed to read the offsets of connector %s: %s", r.name, err.Error())
+	}
+	offsets, _ := resp.Payload.(kafkaconnect.Offsets)
+	r.journal.Offsets = &offsets
+
+	message := fmt.Sprintf("%d partition offsets", len(offsets.Offsets))
+	if !r.opts.stop {
+		message += ", read while the connector runs on the source cluster"
+	}
+	return StatusDone, message, nil
+}
+
+func (r *run) createTarget() (string, string, error) {
+	config, err := r.sourceConfig()
+	if err != nil {
+		return "", "", err
+	}
+	connector := kafkaconnect.Connector{Name: r.name, Config: config}
+	state := kafkaconnect.StateRunning
+	if r.copying() {
+		// Older workers ignore the initial state, the connector would start from scratch
+		// before its offsets are copied.
+		if err := r.checkTargetVersion(3, 7); err != nil {
+			return "", "", err
+		}
+		connector.InitialState = kafkaconnect.StateStopped
+		state = kafkaconnect.StateStopped
+	}
+	if r.opts.dryRun {
+		return StatusPlanned, "create the connector on the target cluster, " + state, nil
+	}
+
+	message := ""
+	resp, err := r.target.Create(connector)
+	if err != nil {
+		if resp == nil || resp.Result != "conflict" {
+			return "", "", fmt.Errorf("Failed to create connector %s on the target cluster: %s", r.name, err.Error())
+		}
+		// An earlier run may have created the connector without recording it in the journal.
+		if err := r.checkCreated(); err != nil {
+			return "", "", err
+		}
+		message = "the connector was already created on the target cluster"
+	}
+
+	if r.copying() {
+		if err := r.waitState(r.target, "target", kafkaconnect.StateStopped); err != nil {
+			return "", "", err
+		}
+	} else if s, err := status(r.target, r.name); err != nil {
+		return "", "", err
+	} else if s == nil {
+		return "", "", fmt.Errorf("Connector %s has no status on the target cluster", r.name)
+	}
+	return StatusDone, message, nil
+}
+
+// checkTargetVersion fails unless the target cluster runs Kafka Connect major.minor or later.
+func (r *run) checkTargetVersion(major int, minor int) error {
+	resp, err := r.target.ServerInfo()
+	if err != nil {
+		return fmt.Errorf("Failed to read the version of the target cluster: %s", err.Error())
+	}
+	info, _ := resp.Payload.(kafkaconnect.ServerInfo)
+	if !info.AtLeast(major, minor) {
+		return fmt.Errorf("Copying offsets requires Kafka Connect %d.%d on the target cluster, it runs %s, migrate without offsets instead", major, minor, info.Version)
+	}
+	return nil
+}
+
+// checkCreated checks that the connector on the target has the config read from the source.
+func (r *run) checkCreated() error {
+	resp, err := r.target.Read(r.name)
+	if err != nil {
+		return fmt.Errorf("Failed to read connector %s on the target cluster: %s", r.name, err.Error())
+	}
+	config, _ := resp.Payload.(map[string]string)
+	if !reflect.DeepEqual(withoutName(config), withoutName(r.config)) {
+		return fmt.Errorf("Connector %s already exists on the target cluster with another config", r.name)
+	}
+	return nil
+}
+
+func withoutName(config map[string]string) map[string]string {
+	c := make(map[string]string, len(config))
+	for k, v := range config {
+		if k != "name" {
+			c[k] = v
+		}
+	}
+	return c
+}
+
+func (r *run) copyOffsets() (string, string, error) {
+	if !r.copying() {
+		return StatusSkipped, "no offsets to copy", nil
+	}
+	count := len(r.journal.Offsets.Offsets)
+	if r.opts.dryRun {
+		return StatusPlanned, fmt.Sprintf("copy %d partition offsets to the target cluster", count), nil
+	}
+
+	s, err := status(r.target, r.name)
+	if err != nil {
+		return "", "", err
+	}
+	if s == nil || s.Connector.State != kafkaconnect.StateStopped {
+		return "", "", fmt.Errorf("Connector %s must be STOPPED on the target cluster to alter its offsets, it is %s", r.name, stateOf(s))
+	}
+	if _, err := r.target.AlterOffsets(r.name, *r.journal.Offsets); err != nil {
+		return "", "", fmt.Errorf("Failed to alter the offsets of connector %s on the target cluster: %s", r.name, err.Error())
+	}
+	return StatusDone, fmt.Sprintf("%d partition offsets", count), nil
+}
+
+func (r *run) startTarget() (string, string, error) {
+	if r.opts.dryRun {
+		return StatusPlanned, "start the connector on the target cluster", nil
+	}
+	s, err := status(r.target, r.name)
+	if err != nil {
+		return "", "", err
+	}
+	if s == nil {
+		return "", "", fmt.Errorf("Connector %s has no status on the target cluster", r.name)
+	}
+	if s.Connector.State == kafkaconnect.StateStopped || s.Connector.State == kafkaconnect.StatePaused {
+		if _, err := r.target.Resume(r.name); err != nil {
+			return "", "", fmt.Errorf("Failed to resume connector %s on the target cluster: %s", r.name, err.Error())
+		}
+	}
+	if err := r.waitState(r.target, "target", kafkaconnect.StateRunning); err != nil {
+		return "", "", err
+	}
+	return StatusDone, "", nil
+}
+
+func (r *run) retireSource() (string, string, error) {
+	if r.opts.action == PauseSource {
+		if r.opts.dryRun {
+			return StatusPlanned, "pause the connector on the source cluster", nil
+		}
+		if _, err := r.source.Pause(r.name); err != nil {
+			return "", "", fmt.Errorf("Failed to pause connector %s on the source cluster: %s", r.name, err.Error())
+		}
+		if err := r.waitState(r.source, "source", kafkaconnect.StatePaused); err != nil {
+			return "", "", err
+		}
+		return StatusDone, "paused on the source cluster", nil
+	}
+
+	if r.opts.dryRun {
+		return StatusPlanned, "delete the connector on the source cluster", nil
+	}
+	if resp, err := r.source.Delete(r.name); err != nil && (resp == nil || resp.Result != "notfound") {
+		return "", "", fmt.Errorf("Failed to delete connector %s on the source cluster: %s", r.name, err.Error())
+	}
+	if err := r.waitState(r.source, "source", ""); err != nil {
+		return "", "", err
+	}
+	return StatusDone, "deleted on the source cluster", nil
+}
+
+// waitState polls the status of the connector on c until it is in state, or until it no
+// longer exists when state is empty. A running connector must also have tasks, all of them
+// running. A connector or task failing ends the wait.
+func (r *run) waitState(c Client, cluster string, state string) error {
+	want := state
+	if want == "" {
+		want = "deleted"
+	}
+	deadline := time.Now().Add(r.opts.timeout)
+	for {
+		s, err := status(c, r.name)
+		if err != nil {
+			return err
+		}
+		switch {
+		case s == nil && state == "":
+			return nil
+		case s != nil && s.IsConnectorFailed():
+			return fmt.Errorf("Connector %s failed on the %s cluster: %s", r.name, cluster, s.Connector.Trace)
+		case s != nil && len(s.GetFailedTasks()) > 0:
+			task := s.Tasks[s.GetFailedTasks()[0]]
+			return fmt.Errorf("Task %d of connector %s failed on the %s cluster: %s", task.ID, r.name, cluster, task.Trace)
+		case s != nil && s.Connector.State == state && (state != kafkaconnect.StateRunning || (s.GetTaskCount() > 0 && s.GetActiveTasksCount() == s.GetTaskCount())):
+			return nil
+		}
+		if !time.Now().Before(deadline) {
+			return fmt.Errorf("Timed out after %s waiting for connector %s to be %s on the %s cluster, it is %s", r.opts.timeout, r.name, want, cluster, stateOf(s))
+		}
+		time.Sleep(r.opts.interval)
+	}
+}
+
+// status returns the status of the connector name on c, or nil when it does not exist.
+func status(c kafkaconnect.KafkaConnectClient, name string) (*kafkaconnect.Status, error) {
+	resp, err := c.GetStatus(name)
+	if err != nil {
+		if resp != nil && resp.Result == "notfound" {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Failed to get the status of connector %s: %s", name, err.Error())
+	}
+	s, _ := resp.Payload.(kafkaconnect.Status)
+	return &s, nil
+}
+
+func stateOf(s *kafkaconnect.Status) string {
+	if s == nil {
+		return "missing"
+	}
+	return s.Connector.State
+}
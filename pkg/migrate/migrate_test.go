@@ -0,0 +1,237 @@
+package migrate_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/walmartdigital/go-kaya/pkg/kafkaconnect"
+	"github.com/walmartdigital/go-kaya/pkg/kafkaconnect/kafkaconnecttest"
+	"github.com/walmartdigital/go-kaya/pkg/migrate"
+)
+
+func TestAll(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Migrate")
+}
+
+var _ = Describe("Migrator", func() {
+	var (
+		source, target             *kafkaconnecttest.Server
+		sourceClient, targetClient migrate.Client
+		dir                        string
+		offsets                    []kafkaconnecttest.Offset
+	)
+
+	config := map[string]string{
+		"name":            "orders",
+		"connector.class": "org.apache.kafka.connect.file.FileStreamSourceConnector",
+		"tasks.max":       "1",
+		"file":            "/var/log/orders.log",
+		"topic":           "orders",
+	}
+
+	newClient := func(s *kafkaconnecttest.Server) migrate.Client {
+		c, err := kafkaconnect.New(s.URL)
+		Expect(err).To(BeNil())
+		return c
+	}
+
+	migrator := func(opts ...migrate.Option) *migrate.Migrator {
+		opts = append([]migrate.Option{migrate.WithWait(time.Second, 10*time.Millisecond)}, opts...)
+		return migrate.New(sourceClient, targetClient, opts...)
+	}
+
+	statuses := func(result *migrate.Result) map[string]string {
+		s := map[string]string{}
+		for _, step := range result.Steps {
+			s[step.Name] = step.Status
+		}
+		return s
+	}
+
+	BeforeEach(func() {
+		source = kafkaconnecttest.NewServer()
+		target = kafkaconnecttest.NewServer()
+		sourceClient = newClient(source)
+		targetClient = newClient(target)
+
+		source.AddConnector(kafkaconnect.Connector{Name: "orders", Config: config})
+		offsets = []kafkaconnecttest.Offset{{
+			Partition: map[string]interface{}{"filename": "/var/log/orders.log"},
+			Offset:    map[string]interface{}{"position": float64(4096)},
+		}}
+		Expect(source.SetOffsets("orders", offsets)).To(Succeed())
+
+		var err error
+		dir, err = ioutil.TempDir("", "migrate")
+		Expect(err).To(BeNil())
+	})
+
+	AfterEach(func() {
+		source.Close()
+		target.Close()
+		os.RemoveAll(dir)
+	})
+
+	It("should move a stopped connector with its offsets", func() {
+		result, err := migrator(migrate.WithStop()).Migrate("orders")
+		Expect(err).To(BeNil())
+		Expect(statuses(result)).To(Equal(map[string]string{
+			migrate.StepRead:         migrate.StatusDone,
+			migrate.StepStopSource:   migrate.StatusDone,
+			migrate.StepReadOffsets:  migrate.StatusDone,
+			migrate.StepCreateTarget: migrate.StatusDone,
+			migrate.StepCopyOffsets:  migrate.StatusDone,
+			migrate.StepStartTarget:  migrate.StatusDone,
+			migrate.StepRetireSource: migrate.StatusDone,
+		}))
+
+		Expect(source.Connectors()).To(BeEmpty())
+		migrated, ok := target.Connector("orders")
+		Expect(ok).To(BeTrue())
+		Expect(migrated.Config).To(Equal(config))
+		migratedOffsets, _ := target.Offsets("orders")
+		Expect(migratedOffsets).To(Equal(offsets))
+		status, _ := target.Status("orders")
+		Expect(status.Connector.State).To(Equal(kafkaconnect.StateRunning))
+		Expect(target.Requests()).To(ContainElement("PATCH /connectors/orders/offsets"))
+	})
+
+	It("should pause the connector on the source when asked to", func() {
+		_, err := migrator(migrate.WithSourceAction(migrate.PauseSource)).Migrate("orders")
+		Expect(err).To(BeNil())
+
+		status, ok := source.Status("orders")
+		Expect(ok).To(BeTrue())
+		Expect(status.Connector.State).To(Equal(kafkaconnect.StatePaused))
+		_, ok = target.Connector("orders")
+		Expect(ok).To(BeTrue())
+	})
+
+	It("should only read the clusters in a dry run", func() {
+		journal := filepath.Join(dir, "orders.json")
+		result, err := migrator(migrate.WithStop(), migrate.WithDryRun(), migrate.WithJournal(journal)).Migrate("orders")
+		Expect(err).To(BeNil())
+		Expect(result.DryRun).To(BeTrue())
+		Expect(statuses(result)).To(Equal(map[string]string{
+			migrate.StepRead:         migrate.StatusDone,
+			migrate.StepStopSource:   migrate.StatusPlanned,
+			migrate.StepReadOffsets:  migrate.StatusDone,
+			migrate.StepCreateTarget: migrate.StatusPlanned,
+			migrate.StepCopyOffsets:  migrate.StatusPlanned,
+			migrate.StepStartTarget:  migrate.StatusPlanned,
+			migrate.StepRetireSource: migrate.StatusPlanned,
+		}))
+
+		for _, r := range append(source.Requests(), target.Requests()...) {
+			Expect(r).To(HavePrefix("GET "))
+		}
+		Expect(journal).NotTo(BeAnExistingFile())
+	})
+
+	It("should skip the offsets when the source does not support them", func() {
+		source.SetOffsetsSupported(false)
+		result, err := migrator().Migrate("orders")
+		Expect(err).To(BeNil())
+		Expect(statuses(result)).To(HaveKeyWithValue(migrate.StepReadOffsets, migrate.StatusSkipped))
+		Expect(statuses(result)).To(HaveKeyWithValue(migrate.StepCopyOffsets, migrate.StatusSkipped))
+		Expect(target.Requests()).NotTo(ContainElement(ContainSubstring("/offsets")))
+	})
+
+	It("should only copy offsets to a target running Kafka Connect 3.7", func() {
+		target.SetVersion("3.6.2")
+		_, err := migrator().Migrate("orders")
+		Expect(err).To(MatchError("Step create-target failed: Copying offsets requires Kafka Connect 3.7 on the target cluster, it runs 3.6.2, migrate without offsets instead"))
+		Expect(target.Connectors()).To(BeEmpty())
+
+		result, err := migrator(migrate.WithoutOffsets()).Migrate("orders")
+		Expect(err).To(BeNil())
+		Expect(statuses(result)).To(HaveKeyWithValue(migrate.StepCopyOffsets, migrate.StatusSkipped))
+		Expect(target.Connectors()).To(ConsistOf("orders"))
+	})
+
+	It("should refuse to replace a connector of the target", func() {
+		target.AddConnector(kafkaconnect.Connector{Name: "orders", Config: map[string]string{"tasks.max": "1"}})
+		_, err := migrator().Migrate("orders")
+		Expect(err).To(MatchError("Step read failed: Connector orders already exists on the target cluster"))
+		Expect(source.Connectors()).To(ConsistOf("orders"))
+	})
+
+	It("should resume a failed migration from its journal", func() {
+		journal := filepath.Join(dir, "orders.json")
+		target.Inject(kafkaconnecttest.Fault{Method: http.MethodPut, Path: "/connectors/*/resume", Status: 500, Message: "Worker unavailable"})
+
+		result, err := migrator(migrate.WithStop(), migrate.WithJournal(journal)).Migrate("orders")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(HavePrefix("Step start-target failed: Failed to resume connector orders on the target cluster"))
+		Expect(result.Steps).To(HaveLen(5))
+
+		j, err := migrate.OpenJournal(journal)
+		Expect(err).To(BeNil())
+		Expect(j.Completed).To(Equal([]string{
+			migrate.StepRead, migrate.StepStopSource, migrate.StepReadOffsets, migrate.StepCreateTarget, migrate.StepCopyOffsets,
+		}))
+		data, err := ioutil.ReadFile(journal)
+		Expect(err).To(BeNil())
+		Expect(string(data)).NotTo(ContainSubstring("connector.class"))
+
+		// The source is gone, the second run relies on the journal.
+		target.ClearFaults()
+		source.AddConnector(kafkaconnect.Connector{Name: "orders", Config: map[string]string{"tasks.max": "1"}})
+		Expect(source.SetOffsets("orders", nil)).To(Succeed())
+		result, err = migrator(migrate.WithStop(), migrate.WithJournal(journal)).Migrate("orders")
+		Expect(err).To(BeNil())
+		Expect(statuses(result)).To(Equal(map[string]string{
+			migrate.StepRead:         migrate.StatusResumed,
+			migrate.StepStopSource:   migrate.StatusResumed,
+			migrate.StepReadOffsets:  migrate.StatusResumed,
+			migrate.StepCreateTarget: migrate.StatusResumed,
+			migrate.StepCopyOffsets:  migrate.StatusResumed,
+			migrate.StepStartTarget:  migrate.StatusDone,
+			migrate.StepRetireSource: migrate.StatusDone,
+		}))
+		migratedOffsets, _ := target.Offsets("orders")
+		Expect(migratedOffsets).To(Equal(offsets))
+		Expect(source.Connectors()).To(BeEmpty())
+
+		_, err = migrator(migrate.WithJournal(journal)).Migrate("payments")
+		Expect(err).To(MatchError(ContainSubstring("records the migration of connector orders, not payments")))
+	})
+
+	It("should continue when an earlier run created the connector without journaling it", func() {
+		journal := filepath.Join(dir, "orders.json")
+		_, err := migrator(migrate.WithJournal(journal), migrate.WithoutOffsets()).Migrate("orders")
+		Expect(err).To(BeNil())
+
+		// Forget the last steps, as if the process died right after creating the connector.
+		j, err := migrate.OpenJournal(journal)
+		Expect(err).To(BeNil())
+		Expect(j.Completed).To(HaveLen(7))
+		source.AddConnector(kafkaconnect.Connector{Name: "orders", Config: config})
+		Expect(ioutil.WriteFile(journal, []byte(`{"connector":"orders","completed":["read","stop-source","read-offsets"]}`), 0600)).To(Succeed())
+
+		result, err := migrator(migrate.WithJournal(journal), migrate.WithoutOffsets()).Migrate("orders")
+		Expect(err).To(BeNil())
+		Expect(result.Steps[3]).To(Equal(migrate.Step{
+			Name:    migrate.StepCreateTarget,
+			Status:  migrate.StatusDone,
+			Message: "the connector was already created on the target cluster",
+		}))
+		Expect(source.Connectors()).To(BeEmpty())
+	})
+
+	It("should fail when the connector fails on the target", func() {
+		// The target status is read once by each step before start-target waits for it.
+		target.Inject(kafkaconnecttest.FailTaskAfter("orders", 0, 4))
+		_, err := migrator(migrate.WithStop()).Migrate("orders")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(HavePrefix("Step start-target failed: Task 0 of connector orders failed on the target cluster"))
+		Expect(source.Connectors()).To(ConsistOf("orders"))
+	})
+})
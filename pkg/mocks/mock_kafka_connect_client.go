@@ -94,40 +94,371 @@ func (mr *MockKafkaConnectClientMockRecorder) Delete(connector interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockKafkaConnectClient)(nil).Delete), connector)
 }
 
-// MockClientFactory is a mock of ClientFactory interface
-type MockClientFactory struct {
+// GetStatus mocks base method
+func (m *MockKafkaConnectClient) GetStatus(connector string) (*kafkaconnect.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatus", connector)
+	ret0, _ := ret[0].(*kafkaconnect.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatus indicates an expected call of GetStatus
+func (mr *MockKafkaConnectClientMockRecorder) GetStatus(connector interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatus", reflect.TypeOf((*MockKafkaConnectClient)(nil).GetStatus), connector)
+}
+
+// RestartTask mocks base method
+func (m *MockKafkaConnectClient) RestartTask(connector string, taskID int) (*kafkaconnect.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestartTask", connector, taskID)
+	ret0, _ := ret[0].(*kafkaconnect.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestartTask indicates an expected call of RestartTask
+func (mr *MockKafkaConnectClientMockRecorder) RestartTask(connector, taskID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestartTask", reflect.TypeOf((*MockKafkaConnectClient)(nil).RestartTask), connector, taskID)
+}
+
+// RestartConnector mocks base method
+func (m *MockKafkaConnectClient) RestartConnector(connector string) (*kafkaconnect.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestartConnector", connector)
+	ret0, _ := ret[0].(*kafkaconnect.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestartConnector indicates an expected call of RestartConnector
+func (mr *MockKafkaConnectClientMockRecorder) RestartConnector(connector interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestartConnector", reflect.TypeOf((*MockKafkaConnectClient)(nil).RestartConnector), connector)
+}
+
+// MockConnectorManager is a mock of ConnectorManager interface
+type MockConnectorManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockConnectorManagerMockRecorder
+}
+
+// MockConnectorManagerMockRecorder is the mock recorder for MockConnectorManager
+type MockConnectorManagerMockRecorder struct {
+	mock *MockConnectorManager
+}
+
+// NewMockConnectorManager creates a new mock instance
+func NewMockConnectorManager(ctrl *gomock.Controller) *MockConnectorManager {
+	mock := &MockConnectorManager{ctrl: ctrl}
+	mock.recorder = &MockConnectorManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockConnectorManager) EXPECT() *MockConnectorManagerMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockConnectorManager) Create(connector kafkaconnect.Connector) (*kafkaconnect.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", connector)
+	ret0, _ := ret[0].(*kafkaconnect.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create
+func (mr *MockConnectorManagerMockRecorder) Create(connector interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockConnectorManager)(nil).Create), connector)
+}
+
+// Read mocks base method
+func (m *MockConnectorManager) Read(connector string) (*kafkaconnect.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Read", connector)
+	ret0, _ := ret[0].(*kafkaconnect.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Read indicates an expected call of Read
+func (mr *MockConnectorManagerMockRecorder) Read(connector interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*MockConnectorManager)(nil).Read), connector)
+}
+
+// Update mocks base method
+func (m *MockConnectorManager) Update(connector kafkaconnect.Connector) (*kafkaconnect.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", connector)
+	ret0, _ := ret[0].(*kafkaconnect.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update
+func (mr *MockConnectorManagerMockRecorder) Update(connector interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockConnectorManager)(nil).Update), connector)
+}
+
+// Delete mocks base method
+func (m *MockConnectorManager) Delete(connector string) (*kafkaconnect.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", connector)
+	ret0, _ := ret[0].(*kafkaconnect.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockConnectorManagerMockRecorder) Delete(connector interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockConnectorManager)(nil).Delete), connector)
+}
+
+// GetStatus mocks base method
+func (m *MockConnectorManager) GetStatus(connector string) (*kafkaconnect.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatus", connector)
+	ret0, _ := ret[0].(*kafkaconnect.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatus indicates an expected call of GetStatus
+func (mr *MockConnectorManagerMockRecorder) GetStatus(connector interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatus", reflect.TypeOf((*MockConnectorManager)(nil).GetStatus), connector)
+}
+
+// RestartTask mocks base method
+func (m *MockConnectorManager) RestartTask(connector string, taskID int) (*kafkaconnect.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestartTask", connector, taskID)
+	ret0, _ := ret[0].(*kafkaconnect.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestartTask indicates an expected call of RestartTask
+func (mr *MockConnectorManagerMockRecorder) RestartTask(connector, taskID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestartTask", reflect.TypeOf((*MockConnectorManager)(nil).RestartTask), connector, taskID)
+}
+
+// RestartConnector mocks base method
+func (m *MockConnectorManager) RestartConnector(connector string) (*kafkaconnect.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestartConnector", connector)
+	ret0, _ := ret[0].(*kafkaconnect.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestartConnector indicates an expected call of RestartConnector
+func (mr *MockConnectorManagerMockRecorder) RestartConnector(connector interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestartConnector", reflect.TypeOf((*MockConnectorManager)(nil).RestartConnector), connector)
+}
+
+// List mocks base method
+func (m *MockConnectorManager) List() (*kafkaconnect.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List")
+	ret0, _ := ret[0].(*kafkaconnect.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockConnectorManagerMockRecorder) List() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockConnectorManager)(nil).List))
+}
+
+// ListStatus mocks base method
+func (m *MockConnectorManager) ListStatus() (*kafkaconnect.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStatus")
+	ret0, _ := ret[0].(*kafkaconnect.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStatus indicates an expected call of ListStatus
+func (mr *MockConnectorManagerMockRecorder) ListStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStatus", reflect.TypeOf((*MockConnectorManager)(nil).ListStatus))
+}
+
+// Apply mocks base method
+func (m *MockConnectorManager) Apply(connector kafkaconnect.Connector) (*kafkaconnect.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Apply", connector)
+	ret0, _ := ret[0].(*kafkaconnect.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Apply indicates an expected call of Apply
+func (mr *MockConnectorManagerMockRecorder) Apply(connector interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Apply", reflect.TypeOf((*MockConnectorManager)(nil).Apply), connector)
+}
+
+// Validate mocks base method
+func (m *MockConnectorManager) Validate(connector kafkaconnect.Connector) (*kafkaconnect.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Validate", connector)
+	ret0, _ := ret[0].(*kafkaconnect.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Validate indicates an expected call of Validate
+func (mr *MockConnectorManagerMockRecorder) Validate(connector interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockConnectorManager)(nil).Validate), connector)
+}
+
+// MockLifecycleManager is a mock of LifecycleManager interface
+type MockLifecycleManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockLifecycleManagerMockRecorder
+}
+
+// MockLifecycleManagerMockRecorder is the mock recorder for MockLifecycleManager
+type MockLifecycleManagerMockRecorder struct {
+	mock *MockLifecycleManager
+}
+
+// NewMockLifecycleManager creates a new mock instance
+func NewMockLifecycleManager(ctrl *gomock.Controller) *MockLifecycleManager {
+	mock := &MockLifecycleManager{ctrl: ctrl}
+	mock.recorder = &MockLifecycleManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockLifecycleManager) EXPECT() *MockLifecycleManagerMockRecorder {
+	return m.recorder
+}
+
+// Pause mocks base method
+func (m *MockLifecycleManager) Pause(connector string) (*kafkaconnect.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pause", connector)
+	ret0, _ := ret[0].(*kafkaconnect.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Pause indicates an expected call of Pause
+func (mr *MockLifecycleManagerMockRecorder) Pause(connector interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pause", reflect.TypeOf((*MockLifecycleManager)(nil).Pause), connector)
+}
+
+// Resume mocks base method
+func (m *MockLifecycleManager) Resume(connector string) (*kafkaconnect.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resume", connector)
+	ret0, _ := ret[0].(*kafkaconnect.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resume indicates an expected call of Resume
+func (mr *MockLifecycleManagerMockRecorder) Resume(connector interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resume", reflect.TypeOf((*MockLifecycleManager)(nil).Resume), connector)
+}
+
+// Stop mocks base method
+func (m *MockLifecycleManager) Stop(connector string) (*kafkaconnect.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stop", connector)
+	ret0, _ := ret[0].(*kafkaconnect.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stop indicates an expected call of Stop
+func (mr *MockLifecycleManagerMockRecorder) Stop(connector interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockLifecycleManager)(nil).Stop), connector)
+}
+
+// GetOffsets mocks base method
+func (m *MockLifecycleManager) GetOffsets(connector string) (*kafkaconnect.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOffsets", connector)
+	ret0, _ := ret[0].(*kafkaconnect.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOffsets indicates an expected call of GetOffsets
+func (mr *MockLifecycleManagerMockRecorder) GetOffsets(connector interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOffsets", reflect.TypeOf((*MockLifecycleManager)(nil).GetOffsets), connector)
+}
+
+// AlterOffsets mocks base method
+func (m *MockLifecycleManager) AlterOffsets(connector string, offsets kafkaconnect.Offsets) (*kafkaconnect.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AlterOffsets", connector, offsets)
+	ret0, _ := ret[0].(*kafkaconnect.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AlterOffsets indicates an expected call of AlterOffsets
+func (mr *MockLifecycleManagerMockRecorder) AlterOffsets(connector, offsets interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AlterOffsets", reflect.TypeOf((*MockLifecycleManager)(nil).AlterOffsets), connector, offsets)
+}
+
+// MockKafkaConnectClientFactory is a mock of KafkaConnectClientFactory interface
+type MockKafkaConnectClientFactory struct {
 	ctrl     *gomock.Controller
-	recorder *MockClientFactoryMockRecorder
+	recorder *MockKafkaConnectClientFactoryMockRecorder
 }
 
-// MockClientFactoryMockRecorder is the mock recorder for MockClientFactory
-type MockClientFactoryMockRecorder struct {
-	mock *MockClientFactory
+// MockKafkaConnectClientFactoryMockRecorder is the mock recorder for MockKafkaConnectClientFactory
+type MockKafkaConnectClientFactoryMockRecorder struct {
+	mock *MockKafkaConnectClientFactory
 }
 
-// NewMockClientFactory creates a new mock instance
-func NewMockClientFactory(ctrl *gomock.Controller) *MockClientFactory {
-	mock := &MockClientFactory{ctrl: ctrl}
-	mock.recorder = &MockClientFactoryMockRecorder{mock}
+// NewMockKafkaConnectClientFactory creates a new mock instance
+func NewMockKafkaConnectClientFactory(ctrl *gomock.Controller) *MockKafkaConnectClientFactory {
+	mock := &MockKafkaConnectClientFactory{ctrl: ctrl}
+	mock.recorder = &MockKafkaConnectClientFactoryMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use
-func (m *MockClientFactory) EXPECT() *MockClientFactoryMockRecorder {
+func (m *MockKafkaConnectClientFactory) EXPECT() *MockKafkaConnectClientFactoryMockRecorder {
 	return m.recorder
 }
 
 // Create mocks base method
-func (m *MockClientFactory) Create(arg0 string, arg1 client.HTTPClientFactory) (kafkaconnect.Client, error) {
+func (m *MockKafkaConnectClientFactory) Create(arg0 string, arg1 client.HTTPClientFactory) (kafkaconnect.KafkaConnectClient, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Create", arg0, arg1)
-	ret0, _ := ret[0].(kafkaconnect.Client)
+	ret0, _ := ret[0].(kafkaconnect.KafkaConnectClient)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create
-func (mr *MockClientFactoryMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockKafkaConnectClientFactoryMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockClientFactory)(nil).Create), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockKafkaConnectClientFactory)(nil).Create), arg0, arg1)
 }
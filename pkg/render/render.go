@@ -0,0 +1,333 @@
+// Package render builds connector definitions from templates, so a single base config
+// can be shared across environments that only differ in a few settings.
+//
+// A base manifest and any number of overlay manifests, in JSON or YAML, are rendered as Go
+// templates with the merged values files, then the overlays are merged on top of the base:
+//
+//	t := render.Template{Base: "es-sink.json", Overlays: []string{"prod/es-sink.json"}, Values: values}
+//	connector, err := t.Render()
+//
+// Values files are YAML or JSON. Within templates values are accessed by path, e.g.
+// {{ .elasticsearch.url }}, and referencing a missing value is an error. Optional values
+// are looked up with the value function instead: {{ value "tasks" | default 1 }}. Lists
+// can be written with {{ json .topics }}, since list config values are joined with commas.
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/walmartdigital/go-kaya/pkg/kafkaconnect"
+	"github.com/walmartdigital/go-kaya/pkg/manifest"
+	"gopkg.in/yaml.v2"
+)
+
+// Values are the variables available to templates.
+type Values map[string]interface{}
+
+// ParseValues decodes a YAML or JSON values document.
+func ParseValues(content []byte) (Values, error) {
+	var raw map[interface{}]interface{}
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, err
+	}
+	values := Values{}
+	for k, v := range raw {
+		values[fmt.Sprint(k)] = normalize(v)
+	}
+	return values, nil
+}
+
+// LoadValues reads and merges values files in order, later files taking precedence.
+func LoadValues(files ...string) (Values, error) {
+	values := Values{}
+	for _, file := range files {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		v, err := ParseValues(content)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid values file %s: %s", file, err.Error())
+		}
+		values = values.Merge(v)
+	}
+	return values, nil
+}
+
+// Merge returns a copy of v with other deep merged on top of it.
+func (v Values) Merge(other Values) Values {
+	return Values(mergeMaps(v, other))
+}
+
+// Set assigns a value given as path=value, e.g. elasticsearch.url=http://elasticsearch,
+// creating intermediate maps as needed.
+func (v Values) Set(assignment string) error {
+	i := strings.Index(assignment, "=")
+	if i <= 0 {
+		return fmt.Errorf("Invalid value assignment '%s', expected path=value", assignment)
+	}
+	keys := strings.Split(assignment[:i], ".")
+	current := map[string]interface{}(v)
+	for _, k := range keys[:len(keys)-1] {
+		next, ok := current[k].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			current[k] = next
+		}
+		current = next
+	}
+	current[keys[len(keys)-1]] = assignment[i+1:]
+	return nil
+}
+
+// lookup returns the value at a dotted path, or nil when it is not set.
+func (v Values) lookup(path string) interface{} {
+	var current interface{} = map[string]interface{}(v)
+	for _, k := range strings.Split(path, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = m[k]
+	}
+	return current
+}
+
+func mergeMaps(base map[string]interface{}, overlay map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(overlay))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range overlay {
+		baseMap, baseIsMap := merged[k].(map[string]interface{})
+		overlayMap, overlayIsMap := v.(map[string]interface{})
+		if baseIsMap && overlayIsMap {
+			merged[k] = mergeMaps(baseMap, overlayMap)
+			continue
+		}
+		merged[k] = v
+	}
+	return merged
+}
+
+// normalize converts the map[interface{}]interface{} produced by the YAML decoder into
+// map[string]interface{} so values can be merged and accessed uniformly.
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			m[fmt.Sprint(k)] = normalize(val)
+		}
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			m[k] = normalize(val)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(v))
+		for i, val := range v {
+			l[i] = normalize(val)
+		}
+		return l
+	default:
+		return value
+	}
+}
+
+var funcs = template.FuncMap{
+	"default": func(def interface{}, value interface{}) interface{} {
+		if value == nil || value == "" {
+			return def
+		}
+		return value
+	},
+	"required": func(msg string, value interface{}) (interface{}, error) {
+		if value == nil || value == "" {
+			return nil, fmt.Errorf("%s", msg)
+		}
+		return value, nil
+	},
+	"join": func(sep string, list interface{}) (string, error) {
+		switch l := list.(type) {
+		case []string:
+			return strings.Join(l, sep), nil
+		case []interface{}:
+			items := make([]string, len(l))
+			for i, item := range l {
+				items[i] = fmt.Sprint(item)
+			}
+			return strings.Join(items, sep), nil
+		case string:
+			return l, nil
+		default:
+			return "", fmt.Errorf("Cannot join %T", list)
+		}
+	},
+	"quote": func(value interface{}) (string, error) {
+		b, err := json.Marshal(fmt.Sprint(value))
+		return string(b), err
+	},
+	"json": func(value interface{}) (string, error) {
+		b, err := json.Marshal(value)
+		return string(b), err
+	},
+	"env": os.Getenv,
+}
+
+// Render executes content as a Go template with values. The name is used in error
+// messages.
+func Render(name string, content []byte, values Values) ([]byte, error) {
+	t, err := template.New(name).Funcs(funcs).Funcs(template.FuncMap{"value": values.lookup}).Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, map[string]interface{}(values)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Template describes connectors built from base definitions and overlays. Definitions
+// are manifests as read by the manifest package, rendered before they are parsed.
+type Template struct {
+	// Base is the path of the base connector definitions, a file or a directory.
+	Base string
+	// Overlays are applied in order on top of the base. When the base defines a single
+	// connector every overlay document applies to it, otherwise overlay documents are
+	// matched by connector name. A null config value removes the setting.
+	Overlays []string
+	// Values are available to the base and overlays.
+	Values Values
+}
+
+// Render renders the base and overlays into a single connector.
+func (t Template) Render() (*kafkaconnect.Connector, error) {
+	connectors, err := t.RenderAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(connectors) != 1 {
+		return nil, fmt.Errorf("Expected a single connector in %s, found %d", t.Base, len(connectors))
+	}
+	return &connectors[0], nil
+}
+
+// RenderAll renders the base and overlays into every connector they define.
+func (t Template) RenderAll() ([]kafkaconnect.Connector, error) {
+	base, err := t.load(t.Base)
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range t.Overlays {
+		docs, err := t.load(o)
+		if err != nil {
+			return nil, err
+		}
+		if err := overlay(base, docs); err != nil {
+			return nil, err
+		}
+	}
+	return manifest.Connectors(base)
+}
+
+// RenderBytes is like Render for definitions held in memory. The first document is the
+// base.
+func (t Template) RenderBytes(documents ...[]byte) (*kafkaconnect.Connector, error) {
+	var base []manifest.Document
+	for i, content := range documents {
+		docs, err := t.parse(fmt.Sprintf("document %d", i), content)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			base = docs
+			continue
+		}
+		if err := overlay(base, docs); err != nil {
+			return nil, err
+		}
+	}
+	connectors, err := manifest.Connectors(base)
+	if err != nil {
+		return nil, err
+	}
+	if len(connectors) != 1 {
+		return nil, fmt.Errorf("Expected a single connector, found %d", len(connectors))
+	}
+	return &connectors[0], nil
+}
+
+func (t Template) load(path string) ([]manifest.Document, error) {
+	files, err := manifest.Files(path)
+	if err != nil {
+		return nil, err
+	}
+	var docs []manifest.Document
+	for _, file := range files {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		d, err := t.parse(file, content)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, d...)
+	}
+	return docs, nil
+}
+
+func (t Template) parse(name string, content []byte) ([]manifest.Document, error) {
+	values := t.Values
+	if values == nil {
+		values = Values{}
+	}
+	rendered, err := Render(name, content, values)
+	if err != nil {
+		return nil, err
+	}
+	return manifest.Parse(name, rendered)
+}
+
+func overlay(base []manifest.Document, docs []manifest.Document) error {
+	for _, o := range docs {
+		target := -1
+		if len(base) == 1 {
+			target = 0
+		} else {
+			for i := range base {
+				if base[i].Connector.Name == o.Connector.Name {
+					target = i
+				}
+			}
+		}
+		if target < 0 {
+			return &manifest.Error{File: o.File, Line: o.Line, Message: fmt.Sprintf("Overlay for unknown connector %s", o.Connector.Name)}
+		}
+
+		connector := &base[target].Connector
+		if o.Connector.Name != "" {
+			connector.Name = o.Connector.Name
+		}
+		if connector.Config == nil {
+			connector.Config = map[string]string{}
+		}
+		for k, v := range o.Connector.Config {
+			connector.Config[k] = v
+		}
+		for _, k := range o.Unset {
+			delete(connector.Config, k)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package render_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/walmartdigital/go-kaya/pkg/render"
+)
+
+func TestAll(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Render")
+}
+
+var _ = Describe("Values", func() {
+	It("should deep merge values files in order", func() {
+		values, err := render.LoadValues("testdata/values.yaml", "testdata/prod/values.yaml")
+		Expect(err).To(BeNil())
+		Expect(values["tasks"]).To(Equal(5))
+		Expect(values["topics"]).To(Equal([]interface{}{"dumblogger-logs", "_ims.logs"}))
+		Expect(values["elasticsearch"]).To(Equal(map[string]interface{}{"url": "https://elasticsearch.prod:9200"}))
+	})
+
+	It("should set nested values", func() {
+		values := render.Values{"elasticsearch": map[string]interface{}{"url": "http://elasticsearch"}}
+		Expect(values.Set("elasticsearch.user=elastic")).To(Succeed())
+		Expect(values.Set("tasks=3")).To(Succeed())
+		Expect(values["elasticsearch"]).To(Equal(map[string]interface{}{"url": "http://elasticsearch", "user": "elastic"}))
+		Expect(values["tasks"]).To(Equal("3"))
+		Expect(values.Set("tasks")).NotTo(Succeed())
+	})
+})
+
+var _ = Describe("Template", func() {
+	It("should render the base config with values", func() {
+		values, err := render.LoadValues("testdata/values.yaml")
+		Expect(err).To(BeNil())
+
+		connector, err := render.Template{Base: "testdata/es-sink.json", Values: values}.Render()
+		Expect(err).To(BeNil())
+		Expect(connector.Name).To(Equal("logging"))
+		Expect(connector.Config["tasks.max"]).To(Equal("1"))
+		Expect(connector.Config["topics"]).To(Equal("dumblogger-logs,_ims.logs"))
+		Expect(connector.Config["connection.url"]).To(Equal("http://elasticsearch"))
+		Expect(connector.Config["connection.password"]).To(Equal("${env:ES_PASSWORD}"))
+		Expect(connector.Config["key.ignore"]).To(Equal("true"))
+	})
+
+	It("should merge overlays on top of the base config", func() {
+		values, err := render.LoadValues("testdata/values.yaml", "testdata/prod/values.yaml")
+		Expect(err).To(BeNil())
+		values["env"] = "prod"
+
+		connector, err := render.Template{
+			Base:     "testdata/es-sink.json",
+			Overlays: []string{"testdata/prod/es-sink.json"},
+			Values:   values,
+		}.Render()
+		Expect(err).To(BeNil())
+		Expect(connector.Name).To(Equal("logging-prod"))
+		Expect(connector.Config["tasks.max"]).To(Equal("5"))
+		Expect(connector.Config["connection.url"]).To(Equal("https://elasticsearch.prod:9200"))
+		Expect(connector.Config["behavior.on.malformed.documents"]).To(Equal("ignore"))
+		Expect(connector.Config).NotTo(HaveKey("batch.size"))
+	})
+
+	It("should render plain connector configs unchanged", func() {
+		connector, err := render.Template{}.RenderBytes([]byte(`{"name": "logging", "config": {"topic.index.map": "logs:<logs-{now/d}>"}}`))
+		Expect(err).To(BeNil())
+		Expect(connector.Config).To(Equal(map[string]string{"topic.index.map": "logs:<logs-{now/d}>"}))
+	})
+
+	It("should fail on missing values", func() {
+		_, err := render.Template{Base: "testdata/es-sink.json"}.Render()
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("topics"))
+	})
+
+	It("should fail on values that cannot be converted to a string", func() {
+		_, err := render.Template{}.RenderBytes([]byte(`{"name": "logging", "config": {"topics": {"a": "b"}}}`))
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("Invalid value for 'topics'"))
+	})
+
+	It("should fail with a required value message", func() {
+		_, err := render.Template{}.RenderBytes([]byte(`{"name": {{ value "name" | required "name is required" | quote }}}`))
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("name is required"))
+	})
+})
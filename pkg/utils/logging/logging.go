@@ -0,0 +1,45 @@
+package logging
+
+import (
+	"github.com/go-logr/logr"
+)
+
+// NullLogger is a logr.Logger that discards everything. It is the default logger of
+// every go-kaya component.
+type NullLogger struct{}
+
+var _ logr.Logger = NullLogger{}
+
+// Info ...
+func (NullLogger) Info(_ string, _ ...interface{}) {}
+
+// Enabled ...
+func (NullLogger) Enabled() bool {
+	return false
+}
+
+// Error ...
+func (NullLogger) Error(_ error, _ string, _ ...interface{}) {}
+
+// V ...
+func (l NullLogger) V(_ int) logr.InfoLogger {
+	return l
+}
+
+// WithName ...
+func (l NullLogger) WithName(_ string) logr.Logger {
+	return l
+}
+
+// WithValues ...
+func (l NullLogger) WithValues(_ ...interface{}) logr.Logger {
+	return l
+}
+
+// OrNull returns log, or a NullLogger if log is nil.
+func OrNull(log logr.Logger) logr.Logger {
+	if log == nil {
+		return NullLogger{}
+	}
+	return log
+}
@@ -1,8 +1,16 @@
+// Package types holds types for config values that Kafka Connect encodes as strings. They
+// marshal to the encoding used by Kafka Connect, and unmarshal from it as well as from
+// native JSON values.
 package types
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // FlexInt ...
@@ -11,6 +19,44 @@ type FlexInt int
 // FlexBool ...
 type FlexBool bool
 
+// FlexList is a list, encoded by Kafka Connect as comma separated values, such as topics.
+type FlexList []string
+
+// FlexMap is a map, encoded by Kafka Connect as comma separated key:value pairs, such as
+// topic.index.map.
+type FlexMap map[string]string
+
+// FlexDuration is a duration, encoded by Kafka Connect in milliseconds, such as linger.ms.
+// Durations are truncated to the millisecond.
+type FlexDuration time.Duration
+
+// Password is a secret config value. It is redacted when printed, but not when marshaled
+// since Kafka Connect needs the actual value.
+type Password string
+
+// RedactedPassword is printed in place of passwords.
+const RedactedPassword = "[hidden]"
+
+// unquote returns the string held by the JSON value b, and whether b is a string.
+func unquote(b []byte) (string, bool, error) {
+	if len(b) == 0 || b[0] != '"' {
+		return "", false, nil
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return "", true, err
+	}
+	return s, true, nil
+}
+
+// marshalText returns text as a JSON string.
+func marshalText(text []byte, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
 // UnmarshalJSON ...
 func (fi *FlexInt) UnmarshalJSON(b []byte) error {
 	if b[0] != '"' {
@@ -28,6 +74,26 @@ func (fi *FlexInt) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// MarshalJSON ...
+func (fi FlexInt) MarshalJSON() ([]byte, error) {
+	return marshalText(fi.MarshalText())
+}
+
+// MarshalText ...
+func (fi FlexInt) MarshalText() ([]byte, error) {
+	return []byte(strconv.Itoa(int(fi))), nil
+}
+
+// UnmarshalText ...
+func (fi *FlexInt) UnmarshalText(text []byte) error {
+	i, err := strconv.Atoi(strings.TrimSpace(string(text)))
+	if err != nil {
+		return errors.New("must be an int")
+	}
+	*fi = FlexInt(i)
+	return nil
+}
+
 // UnmarshalJSON ...
 func (fi *FlexBool) UnmarshalJSON(b []byte) error {
 	if b[0] != '"' {
@@ -44,3 +110,195 @@ func (fi *FlexBool) UnmarshalJSON(b []byte) error {
 	*fi = FlexBool(i)
 	return nil
 }
+
+// MarshalJSON ...
+func (fi FlexBool) MarshalJSON() ([]byte, error) {
+	return marshalText(fi.MarshalText())
+}
+
+// MarshalText ...
+func (fi FlexBool) MarshalText() ([]byte, error) {
+	return []byte(strconv.FormatBool(bool(fi))), nil
+}
+
+// UnmarshalText accepts true and false in any case, as Kafka Connect does.
+func (fi *FlexBool) UnmarshalText(text []byte) error {
+	switch s := strings.TrimSpace(string(text)); {
+	case strings.EqualFold(s, "true"):
+		*fi = true
+	case strings.EqualFold(s, "false"):
+		*fi = false
+	default:
+		return errors.New("must be true or false")
+	}
+	return nil
+}
+
+// UnmarshalJSON accepts a comma separated string or an array of strings.
+func (fl *FlexList) UnmarshalJSON(b []byte) error {
+	s, ok, err := unquote(b)
+	if err != nil {
+		return err
+	}
+	if ok {
+		return fl.UnmarshalText([]byte(s))
+	}
+	return json.Unmarshal(b, (*[]string)(fl))
+}
+
+// MarshalJSON ...
+func (fl FlexList) MarshalJSON() ([]byte, error) {
+	return marshalText(fl.MarshalText())
+}
+
+// MarshalText ...
+func (fl FlexList) MarshalText() ([]byte, error) {
+	return []byte(strings.Join(fl, ",")), nil
+}
+
+// UnmarshalText splits text on commas and trims spaces around items. An empty text is an
+// empty list.
+func (fl *FlexList) UnmarshalText(text []byte) error {
+	s := strings.TrimSpace(string(text))
+	if s == "" {
+		*fl = nil
+		return nil
+	}
+	items := strings.Split(s, ",")
+	for i := range items {
+		items[i] = strings.TrimSpace(items[i])
+	}
+	*fl = items
+	return nil
+}
+
+// UnmarshalJSON accepts a string of comma separated key:value pairs or an object.
+func (fm *FlexMap) UnmarshalJSON(b []byte) error {
+	s, ok, err := unquote(b)
+	if err != nil {
+		return err
+	}
+	if ok {
+		return fm.UnmarshalText([]byte(s))
+	}
+	return json.Unmarshal(b, (*map[string]string)(fm))
+}
+
+// MarshalJSON ...
+func (fm FlexMap) MarshalJSON() ([]byte, error) {
+	return marshalText(fm.MarshalText())
+}
+
+// MarshalText writes the pairs sorted by key.
+func (fm FlexMap) MarshalText() ([]byte, error) {
+	keys := make([]string, 0, len(fm))
+	for k := range fm {
+		if strings.ContainsAny(k, ",:") || strings.Contains(fm[k], ",") {
+			return nil, fmt.Errorf("cannot encode %q:%q, keys must not contain ',' or ':' and values must not contain ','", k, fm[k])
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = k + ":" + fm[k]
+	}
+	return []byte(strings.Join(pairs, ",")), nil
+}
+
+// UnmarshalText reads comma separated key:value pairs. Values may contain colons.
+func (fm *FlexMap) UnmarshalText(text []byte) error {
+	var items FlexList
+	_ = items.UnmarshalText(text)
+	m := make(map[string]string, len(items))
+	for _, item := range items {
+		i := strings.Index(item, ":")
+		if i < 0 {
+			return fmt.Errorf("invalid entry %q, expected key:value", item)
+		}
+		key := strings.TrimSpace(item[:i])
+		if _, ok := m[key]; ok {
+			return fmt.Errorf("duplicate key %q", key)
+		}
+		m[key] = strings.TrimSpace(item[i+1:])
+	}
+	*fm = m
+	return nil
+}
+
+// Duration returns fd as a time.Duration.
+func (fd FlexDuration) Duration() time.Duration {
+	return time.Duration(fd)
+}
+
+// UnmarshalJSON accepts a number of milliseconds, as a number or a string, or a duration
+// string such as "1m30s".
+func (fd *FlexDuration) UnmarshalJSON(b []byte) error {
+	s, ok, err := unquote(b)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		s = string(b)
+	}
+	return fd.UnmarshalText([]byte(s))
+}
+
+// MarshalJSON ...
+func (fd FlexDuration) MarshalJSON() ([]byte, error) {
+	return marshalText(fd.MarshalText())
+}
+
+// MarshalText ...
+func (fd FlexDuration) MarshalText() ([]byte, error) {
+	return []byte(strconv.FormatInt(int64(time.Duration(fd)/time.Millisecond), 10)), nil
+}
+
+// UnmarshalText accepts a number of milliseconds or a duration string such as "1m30s".
+func (fd *FlexDuration) UnmarshalText(text []byte) error {
+	s := strings.TrimSpace(string(text))
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+		*fd = FlexDuration(time.Duration(ms) * time.Millisecond)
+		return nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return errors.New("must be a number of milliseconds")
+	}
+	*fd = FlexDuration(d.Truncate(time.Millisecond))
+	return nil
+}
+
+// String ...
+func (p Password) String() string {
+	if p == "" {
+		return ""
+	}
+	return RedactedPassword
+}
+
+// GoString redacts the password from %#v.
+func (p Password) GoString() string {
+	return strconv.Quote(p.String())
+}
+
+// UnmarshalJSON ...
+func (p *Password) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, (*string)(p))
+}
+
+// MarshalJSON ...
+func (p Password) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(p))
+}
+
+// MarshalText ...
+func (p Password) MarshalText() ([]byte, error) {
+	return []byte(p), nil
+}
+
+// UnmarshalText ...
+func (p *Password) UnmarshalText(text []byte) error {
+	*p = Password(text)
+	return nil
+}
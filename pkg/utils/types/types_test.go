@@ -0,0 +1,114 @@
+package types_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/walmartdigital/go-kaya/pkg/utils/types"
+)
+
+func TestAll(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Types")
+}
+
+type config struct {
+	TasksMax      types.FlexInt      `json:"tasks.max"`
+	KeyIgnore     types.FlexBool     `json:"key.ignore"`
+	Topics        types.FlexList     `json:"topics"`
+	TopicIndexMap types.FlexMap      `json:"topic.index.map"`
+	Linger        types.FlexDuration `json:"linger.ms"`
+	Password      types.Password     `json:"connection.password"`
+}
+
+var _ = Describe("Flex types", func() {
+	It("should marshal to the string encodings of Kafka Connect and back", func() {
+		c := config{
+			TasksMax:      2,
+			KeyIgnore:     true,
+			Topics:        types.FlexList{"_ims.logs", "_dumblogger.logs"},
+			TopicIndexMap: types.FlexMap{"_ims.logs": "ims", "_dumblogger.logs": "dumblogger"},
+			Linger:        types.FlexDuration(1500 * time.Millisecond),
+			Password:      "s3cr3t",
+		}
+		b, err := json.Marshal(c)
+		Expect(err).To(BeNil())
+		Expect(b).To(MatchJSON(`{
+			"tasks.max": "2",
+			"key.ignore": "true",
+			"topics": "_ims.logs,_dumblogger.logs",
+			"topic.index.map": "_dumblogger.logs:dumblogger,_ims.logs:ims",
+			"linger.ms": "1500",
+			"connection.password": "s3cr3t"
+		}`))
+
+		var back config
+		Expect(json.Unmarshal(b, &back)).To(Succeed())
+		Expect(back).To(Equal(c))
+	})
+
+	It("should unmarshal native JSON values", func() {
+		var c config
+		Expect(json.Unmarshal([]byte(`{
+			"tasks.max": 2,
+			"key.ignore": false,
+			"topics": ["logs", "metrics"],
+			"topic.index.map": {"logs": "logs-v2"},
+			"linger.ms": 250
+		}`), &c)).To(Succeed())
+		Expect(c.TasksMax).To(Equal(types.FlexInt(2)))
+		Expect(c.Topics).To(Equal(types.FlexList{"logs", "metrics"}))
+		Expect(c.TopicIndexMap).To(Equal(types.FlexMap{"logs": "logs-v2"}))
+		Expect(c.Linger.Duration()).To(Equal(250 * time.Millisecond))
+	})
+
+	It("should parse lists and maps loosely", func() {
+		var l types.FlexList
+		Expect(l.UnmarshalText([]byte(" logs , metrics"))).To(Succeed())
+		Expect(l).To(Equal(types.FlexList{"logs", "metrics"}))
+		Expect(l.UnmarshalText([]byte(""))).To(Succeed())
+		Expect(l).To(BeEmpty())
+
+		var m types.FlexMap
+		Expect(m.UnmarshalText([]byte("logs: logs-v2, db:jdbc:postgresql"))).To(Succeed())
+		Expect(m).To(Equal(types.FlexMap{"logs": "logs-v2", "db": "jdbc:postgresql"}))
+		Expect(m.UnmarshalText([]byte("logs"))).To(MatchError(`invalid entry "logs", expected key:value`))
+		Expect(m.UnmarshalText([]byte("logs:a,logs:b"))).To(MatchError(`duplicate key "logs"`))
+
+		_, err := types.FlexMap{"logs": "a,b"}.MarshalText()
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should read durations in milliseconds or as duration strings", func() {
+		var d types.FlexDuration
+		Expect(d.UnmarshalText([]byte("60000"))).To(Succeed())
+		Expect(d.Duration()).To(Equal(time.Minute))
+		Expect(json.Unmarshal([]byte(`"1m30s"`), &d)).To(Succeed())
+		Expect(d.Duration()).To(Equal(90 * time.Second))
+		Expect(d.UnmarshalText([]byte("soon"))).To(MatchError("must be a number of milliseconds"))
+
+		text, err := types.FlexDuration(1500 * time.Microsecond).MarshalText()
+		Expect(err).To(BeNil())
+		Expect(string(text)).To(Equal("1"))
+	})
+
+	It("should reject malformed ints and bools", func() {
+		var i types.FlexInt
+		Expect(i.UnmarshalText([]byte("two"))).To(MatchError("must be an int"))
+		var b types.FlexBool
+		Expect(b.UnmarshalText([]byte("TRUE"))).To(Succeed())
+		Expect(bool(b)).To(BeTrue())
+		Expect(b.UnmarshalText([]byte("yes"))).To(MatchError("must be true or false"))
+	})
+
+	It("should redact passwords when printed", func() {
+		c := config{Password: "s3cr3t"}
+		Expect(c.Password.String()).To(Equal(types.RedactedPassword))
+		Expect(fmt.Sprintf("%v %s %+v %#v", c.Password, c.Password, c, c)).NotTo(ContainSubstring("s3cr3t"))
+		Expect(types.Password("").String()).To(BeEmpty())
+	})
+})
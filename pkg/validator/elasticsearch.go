@@ -0,0 +1,247 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// maxIndexNameLength is the maximum length of an Elasticsearch index name, in bytes.
+const maxIndexNameLength = 255
+
+// indexForbiddenChars cannot appear in Elasticsearch index names.
+const indexForbiddenChars = `\/*?"<>|, #:`
+
+// dateMathPattern matches the expression of a date math index name, e.g. now/d,
+// now-1M/M or now/d{yyyy.MM.dd|+12:00}.
+var dateMathPattern = regexp.MustCompile(`^now([+-][0-9]+[yMwdhHms])*(/[yMwdhHms])?(\{[^{}|]+(\|[^{}]+)?\})?$`)
+
+// validateIndexName checks name against the Elasticsearch index naming rules. Date math
+// names such as <logs-{now/d}> are checked on their static parts. Uppercase letters are only
+// allowed when lowercase is false, for names the connector lowercases itself. It returns a
+// description of the problem, or an empty string when the name is valid.
+func validateIndexName(name string, lowercase bool) string {
+	if strings.HasPrefix(name, "<") && strings.HasSuffix(name, ">") {
+		return validateDateMathIndexName(name[1:len(name)-1], lowercase)
+	}
+	return validateStaticIndexName(name, true, lowercase)
+}
+
+// validateStaticIndexName checks a plain index name, or the static part of a date math
+// name. start tells whether the name begins with part.
+func validateStaticIndexName(part string, start bool, lowercase bool) string {
+	if start && (part == "." || part == "..") {
+		return "must not be . or .."
+	}
+	if len(part) > maxIndexNameLength {
+		return fmt.Sprintf("must not be longer than %d bytes", maxIndexNameLength)
+	}
+	if start && part != "" && strings.ContainsAny(part[:1], "-_+") {
+		return "must not start with -, _ or +"
+	}
+	if lowercase && part != strings.ToLower(part) {
+		return "must be lowercase"
+	}
+	if i := strings.IndexAny(part, indexForbiddenChars); i >= 0 {
+		return fmt.Sprintf("must not contain %q", string(part[i]))
+	}
+	return ""
+}
+
+// validateDateMathIndexName checks the content of a <static{date_math}static> index name.
+func validateDateMathIndexName(name string, lowercase bool) string {
+	if name == "" {
+		return "must not be empty"
+	}
+	var static strings.Builder
+	length := 0
+	start := true
+	for i := 0; i < len(name); i++ {
+		switch c := name[i]; c {
+		case '\\':
+			// Escaped braces are part of the static text.
+			if i+1 < len(name) && (name[i+1] == '{' || name[i+1] == '}') {
+				static.WriteByte(name[i+1])
+				i++
+				continue
+			}
+			static.WriteByte(c)
+		case '{':
+			end := matchingBrace(name, i)
+			if end < 0 {
+				return "has an unterminated date math expression"
+			}
+			expr := name[i+1 : end]
+			if !dateMathPattern.MatchString(expr) {
+				return fmt.Sprintf("has an invalid date math expression {%s}", expr)
+			}
+			if msg := validateStaticIndexName(static.String(), start, lowercase); msg != "" {
+				return msg
+			}
+			length += static.Len()
+			static.Reset()
+			start = false
+			i = end
+		case '}':
+			return "has an unbalanced }"
+		default:
+			static.WriteByte(c)
+		}
+	}
+	if msg := validateStaticIndexName(static.String(), start, lowercase); msg != "" {
+		return msg
+	}
+	if start {
+		return "has no date math expression, remove the < and >"
+	}
+	if length+static.Len() > maxIndexNameLength {
+		return fmt.Sprintf("must not be longer than %d bytes", maxIndexNameLength)
+	}
+	return ""
+}
+
+// matchingBrace returns the index of the brace closing the one at open, or -1.
+func matchingBrace(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// topicIndexMap parses topic.index.map, skipping malformed entries which are reported by
+// the topicindexmap validation.
+func topicIndexMap(value string) ([]string, map[string][]string) {
+	var topics []string
+	indices := map[string][]string{}
+	if placeholderPattern.MatchString(value) {
+		return nil, indices
+	}
+	for _, entry := range strings.Split(value, ",") {
+		parts := strings.SplitN(strings.TrimSpace(entry), ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			continue
+		}
+		if _, ok := indices[parts[0]]; !ok {
+			topics = append(topics, parts[0])
+		}
+		indices[parts[0]] = append(indices[parts[0]], parts[1])
+	}
+	return topics, indices
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// elasticsearchRules are the semantic checks of Elasticsearch sink configs.
+var elasticsearchRules = []Rule{
+	{
+		Name: "topic.index.map.topics",
+		Keys: []string{"topic.index.map", "topics", "topics.regex"},
+		Check: func(config map[string]string) string {
+			mapped, _ := topicIndexMap(config["topic.index.map"])
+			if len(mapped) == 0 || placeholderPattern.MatchString(config["topics"]) {
+				return ""
+			}
+			consumed := map[string]bool{}
+			for _, t := range splitList(config["topics"]) {
+				consumed[t] = true
+			}
+			var pattern *regexp.Regexp
+			if regex := config["topics.regex"]; regex != "" {
+				var err error
+				if pattern, err = regexp.Compile("^(?:" + regex + ")$"); err != nil {
+					return ""
+				}
+			}
+			var unknown []string
+			for _, t := range mapped {
+				if !consumed[t] && (pattern == nil || !pattern.MatchString(t)) {
+					unknown = append(unknown, t)
+				}
+			}
+			if len(unknown) > 0 {
+				return fmt.Sprintf("Topics mapped in 'topic.index.map' are not consumed by the connector: %s", strings.Join(unknown, ", "))
+			}
+			return ""
+		},
+	},
+	{
+		Name: "topic.index.map.duplicates",
+		Keys: []string{"topic.index.map"},
+		Check: func(config map[string]string) string {
+			mapped, indices := topicIndexMap(config["topic.index.map"])
+			var duplicates []string
+			for _, t := range mapped {
+				if len(indices[t]) > 1 {
+					duplicates = append(duplicates, t)
+				}
+			}
+			if len(duplicates) > 0 {
+				return fmt.Sprintf("Topics mapped more than once in 'topic.index.map': %s", strings.Join(duplicates, ", "))
+			}
+			return ""
+		},
+	},
+	{
+		Name: "topic.index.map.indices",
+		Keys: []string{"topic.index.map"},
+		Check: func(config map[string]string) string {
+			mapped, indices := topicIndexMap(config["topic.index.map"])
+			var problems []string
+			for _, t := range mapped {
+				for _, index := range indices[t] {
+					if msg := validateIndexName(index, true); msg != "" {
+						problems = append(problems, fmt.Sprintf("%s %s", index, msg))
+					}
+				}
+			}
+			if len(problems) > 0 {
+				return fmt.Sprintf("Invalid index names in 'topic.index.map': %s", strings.Join(problems, "; "))
+			}
+			return ""
+		},
+	},
+	{
+		// Topics missing from topic.index.map are written to the index of the same name.
+		// Kafka Connect accepts such topics, so this is only a warning. The connector
+		// lowercases these names, so uppercase topics are fine.
+		Name:    "topics.indices",
+		Keys:    []string{"topics", "topic.index.map"},
+		Warning: true,
+		Check: func(config map[string]string) string {
+			if placeholderPattern.MatchString(config["topics"]) {
+				return ""
+			}
+			_, indices := topicIndexMap(config["topic.index.map"])
+			var problems []string
+			for _, t := range splitList(config["topics"]) {
+				if _, ok := indices[t]; ok {
+					continue
+				}
+				if msg := validateIndexName(t, false); msg != "" {
+					problems = append(problems, fmt.Sprintf("%s %s", t, msg))
+				}
+			}
+			if len(problems) > 0 {
+				return fmt.Sprintf("Topics not mapped in 'topic.index.map' are not valid index names: %s", strings.Join(problems, "; "))
+			}
+			return ""
+		},
+	},
+}
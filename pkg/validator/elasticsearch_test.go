@@ -0,0 +1,136 @@
+package validator_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/walmartdigital/go-kaya/pkg/manifest"
+	"github.com/walmartdigital/go-kaya/pkg/validator"
+)
+
+var _ = Describe("Elasticsearch sink tests", func() {
+	var (
+		v      *validator.Validator
+		config map[string]string
+	)
+
+	BeforeEach(func() {
+		v = validator.New()
+		config = map[string]string{
+			"name":            "logging",
+			"connector.class": validator.ElasticsearchSinkClass,
+			"connection.url":  "http://elasticsearch:9200",
+			"topics":          "dumblogger-logs,_ims.logs,metrics",
+			"topic.index.map": "dumblogger-logs:<logs-pd-dumblogger-{now/d}>,_ims.logs:ims",
+			"type.name":       "log",
+			"tasks.max":       "5",
+		}
+	})
+
+	It("should accept the e2e manifests", func() {
+		docs, err := manifest.Load("../../e2e/create_connector.json", "../../e2e/create_connector.yaml")
+		Expect(err).To(BeNil())
+		Expect(docs).To(HaveLen(2))
+		for _, d := range docs {
+			d.Connector.Config["name"] = d.Connector.Name
+			report := v.Validate(d.Connector.Config)
+			Expect(report.Valid()).To(BeTrue(), report.Text())
+		}
+	})
+
+	It("should accept a valid config", func() {
+		report := v.Validate(config)
+		Expect(report.Valid()).To(BeTrue(), report.Text())
+	})
+
+	It("should require mapped topics to be consumed", func() {
+		config["topic.index.map"] += ",_amida.logs:amida,_kimun.logs:kimun"
+		report := v.Validate(config)
+		Expect(report.Violations).To(ConsistOf(validator.Violation{
+			Key:     "topic.index.map",
+			Rule:    "topic.index.map.topics",
+			Value:   config["topic.index.map"],
+			Message: "Topics mapped in 'topic.index.map' are not consumed by the connector: _amida.logs, _kimun.logs",
+		}))
+	})
+
+	It("should match mapped topics against topics.regex", func() {
+		delete(config, "topics")
+		config["topics.regex"] = "dumblogger-.*|_ims\\..*"
+		Expect(v.Validate(config).Valid()).To(BeTrue())
+
+		config["topics.regex"] = "dumblogger-.*"
+		report := v.Validate(config)
+		Expect(report.Violations).To(HaveLen(1))
+		Expect(report.Violations[0].Message).To(HaveSuffix("not consumed by the connector: _ims.logs"))
+	})
+
+	It("should reject topics mapped twice", func() {
+		config["topic.index.map"] += ",_ims.logs:ims-2"
+		report := v.Validate(config)
+		Expect(report.Violations).To(HaveLen(1))
+		Expect(report.Violations[0].Message).To(Equal("Topics mapped more than once in 'topic.index.map': _ims.logs"))
+	})
+
+	It("should reject topics and topics.regex together", func() {
+		config["topics.regex"] = "logs-.*"
+		report := v.Validate(config)
+		Expect(report.ByKey("topics")).To(HaveLen(1))
+		Expect(report.ByKey("topics")[0].Message).To(Equal("Only one of 'topics' or 'topics.regex' may be set"))
+	})
+
+	It("should warn about unmapped topics that are not valid index names", func() {
+		config["topics"] += ",_osiris.logs,Audit"
+		report := v.Validate(config)
+		Expect(report.Valid()).To(BeTrue(), report.Text())
+		Expect(report.Warnings).To(HaveLen(1))
+		Expect(report.Warnings[0].Message).To(Equal("Topics not mapped in 'topic.index.map' are not valid index names: _osiris.logs must not start with -, _ or +"))
+		Expect(report.Text()).To(ContainSubstring("1 warning(s):\n  topics (topics.indices): "))
+	})
+
+	DescribeTable("should check index names",
+		func(index string, message string) {
+			config["topic.index.map"] += ",metrics:" + index
+			report := v.Validate(config)
+			if message == "" {
+				Expect(report.Valid()).To(BeTrue(), report.Text())
+				return
+			}
+			Expect(report.ByKey("topic.index.map")).To(ContainElement(validator.Violation{
+				Key:     "topic.index.map",
+				Rule:    "topic.index.map.indices",
+				Value:   config["topic.index.map"],
+				Message: "Invalid index names in 'topic.index.map': " + index + " " + message,
+			}))
+		},
+		Entry("plain", "metrics-2020", ""),
+		Entry("date math", "<metrics-{now/d}>", ""),
+		Entry("date math with offset and format", "<metrics-{now-1M/M{yyyy.MM}}>", ""),
+		Entry("date math in the middle", "<metrics-{now/d}-eu>", ""),
+		Entry("uppercase", "Metrics", "must be lowercase"),
+		Entry("uppercase static part", "<Metrics-{now/d}>", "must be lowercase"),
+		Entry("leading underscore", "_metrics", "must not start with -, _ or +"),
+		Entry("leading dash in date math", "<-metrics-{now/d}>", "must not start with -, _ or +"),
+		Entry("dot", ".", "must not be . or .."),
+		Entry("forbidden character", "metrics/2020", "must not contain \"/\""),
+		Entry("invalid expression", "<metrics-{today}>", "has an invalid date math expression {today}"),
+		Entry("unterminated expression", "<metrics-{now/d>", "has an unterminated date math expression"),
+		Entry("no expression", "<metrics>", "has no date math expression, remove the < and >"),
+	)
+
+	It("should check tasks.max and type.name", func() {
+		config["tasks.max"] = "-1"
+		config["type.name"] = "log/doc"
+		report := v.Validate(config)
+		Expect(report.Violations).To(ConsistOf(
+			validator.Violation{Key: "tasks.max", Rule: "min", Value: "-1", Message: "Invalid value for 'tasks.max': must be at least 1"},
+			validator.Violation{Key: "type.name", Rule: "esdoctype", Value: "log/doc", Message: "Invalid value for 'type.name': failed the esdoctype validation"},
+		))
+	})
+
+	It("should skip semantic checks of config provider references", func() {
+		config["topics"] = "${env:TOPICS}"
+		config["topic.index.map"] = "${file:/etc/kafka/es.properties:topic.index.map}"
+		Expect(v.Validate(config).Valid()).To(BeTrue())
+	})
+})
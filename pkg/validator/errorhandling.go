@@ -0,0 +1,129 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Keys of the dead letter queue of sink connectors.
+var deadLetterQueueKeys = []string{
+	"errors.deadletterqueue.topic.name",
+	"errors.deadletterqueue.topic.replication.factor",
+	"errors.deadletterqueue.context.headers.enable",
+}
+
+// Converters needing a schema registry when set on a connector.
+var registryConverters = []string{
+	"io.confluent.connect.avro.AvroConverter",
+	"io.confluent.connect.protobuf.ProtobufConverter",
+	"io.confluent.connect.json.JsonSchemaConverter",
+}
+
+// errorLogRule requires errors.log.enable to include messages in the error log.
+var errorLogRule = Rule{
+	Name: "errors.log",
+	Keys: []string{"errors.log.include.messages", "errors.log.enable"},
+	Check: func(config map[string]string) string {
+		if isTrue(config["errors.log.include.messages"]) && !isTrue(config["errors.log.enable"]) {
+			return "'errors.log.include.messages' requires 'errors.log.enable' to be true"
+		}
+		return ""
+	},
+}
+
+// deadLetterQueueRule requires a topic for the other dead letter queue settings.
+var deadLetterQueueRule = Rule{
+	Name: "deadletterqueue",
+	Keys: deadLetterQueueKeys,
+	Check: func(config map[string]string) string {
+		if config["errors.deadletterqueue.topic.name"] != "" {
+			return ""
+		}
+		for _, k := range deadLetterQueueKeys[1:] {
+			if config[k] != "" {
+				return fmt.Sprintf("'%s' requires 'errors.deadletterqueue.topic.name'", k)
+			}
+		}
+		return ""
+	},
+}
+
+// deadLetterQueueToleranceRule warns about a dead letter queue with errors.tolerance other
+// than all: Kafka Connect accepts it, but no record is ever sent to the queue.
+var deadLetterQueueToleranceRule = Rule{
+	Name: "deadletterqueue.tolerance",
+	Keys: []string{"errors.deadletterqueue.topic.name", "errors.tolerance"},
+	Check: func(config map[string]string) string {
+		if config["errors.deadletterqueue.topic.name"] != "" && !strings.EqualFold(config["errors.tolerance"], "all") {
+			return "'errors.deadletterqueue.topic.name' is unused unless 'errors.tolerance' is all"
+		}
+		return ""
+	},
+	Warning: true,
+}
+
+// sourceDeadLetterQueueRule rejects dead letter queues, which only sink connectors support.
+var sourceDeadLetterQueueRule = Rule{
+	Name: "deadletterqueue",
+	Keys: deadLetterQueueKeys,
+	Check: func(config map[string]string) string {
+		for _, k := range deadLetterQueueKeys {
+			if config[k] != "" {
+				return fmt.Sprintf("'%s' is only supported by sink connectors", k)
+			}
+		}
+		return ""
+	},
+}
+
+// converterRules require a schema registry URL for the converters of the connector that
+// need one, since they do not inherit the settings of the worker.
+var converterRules = []Rule{
+	requiredWhen("key.converter.schema.registry.url", "key.converter", registryConverters...),
+	requiredWhen("value.converter.schema.registry.url", "value.converter", registryConverters...),
+}
+
+// errorHandlingFields are the error handling and converter settings of every connector.
+var errorHandlingFields = map[string]Field{
+	"errors.tolerance":               {Type: String, Enum: []string{"none", "all"}},
+	"errors.retry.timeout":           {Type: Long, Min: Int64(-1)},
+	"errors.retry.delay.max.ms":      {Type: Long, Min: Int64(0)},
+	"errors.log.enable":              {Type: Boolean},
+	"errors.log.include.messages":    {Type: Boolean},
+	"key.converter.schemas.enable":   {Type: Boolean},
+	"value.converter.schemas.enable": {Type: Boolean},
+}
+
+// deadLetterQueueFields are the dead letter queue settings of sink connectors.
+var deadLetterQueueFields = map[string]Field{
+	"errors.deadletterqueue.topic.name":               {Type: String, Tag: "kafkatopic"},
+	"errors.deadletterqueue.topic.replication.factor": {Type: Int, Min: Int64(-1)},
+	"errors.deadletterqueue.context.headers.enable":   {Type: Boolean},
+}
+
+// sinkRules returns rules along with the rules shared by every sink connector.
+func sinkRules(rules ...Rule) []Rule {
+	return append(append(rules, errorLogRule, deadLetterQueueRule, deadLetterQueueToleranceRule), converterRules...)
+}
+
+// sourceRules returns rules along with the rules shared by every source connector.
+func sourceRules(rules ...Rule) []Rule {
+	return append(append(rules, errorLogRule, sourceDeadLetterQueueRule), converterRules...)
+}
+
+// fallbackSchema returns the schema of connector classes without one, which checks the
+// settings shared by every connector. Sink and source connectors are told apart by the
+// name of their class.
+func fallbackSchema(class string) *Schema {
+	switch {
+	case strings.HasSuffix(class, "SinkConnector"):
+		return &Schema{Class: class, Fields: withSinkCommon(map[string]Field{}), Rules: sinkRules()}
+	case strings.HasSuffix(class, "SourceConnector"):
+		return &Schema{Class: class, Fields: withCommon(map[string]Field{}), Rules: sourceRules()}
+	}
+	return &Schema{Class: class, Fields: withCommon(map[string]Field{}), Rules: append([]Rule{errorLogRule}, converterRules...)}
+}
+
+func isTrue(value string) bool {
+	return strings.EqualFold(strings.TrimSpace(value), "true")
+}
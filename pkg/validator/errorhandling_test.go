@@ -0,0 +1,91 @@
+package validator_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/walmartdigital/go-kaya/pkg/validator"
+)
+
+var _ = Describe("Error handling rules", func() {
+	var v *validator.Validator
+
+	BeforeEach(func() {
+		v = validator.New()
+	})
+
+	connector := func(class string, settings map[string]string) map[string]string {
+		config := map[string]string{"name": "orders", "connector.class": class}
+		switch class {
+		case validator.JDBCSinkClass:
+			config["topics"] = "orders"
+			config["connection.url"] = "jdbc:postgresql://db:5432/shop"
+		case validator.JDBCSourceClass:
+			config["connection.url"] = "jdbc:postgresql://db:5432/shop"
+			config["mode"] = "bulk"
+			config["topic.prefix"] = "shop-"
+		}
+		for k, value := range settings {
+			config[k] = value
+		}
+		return config
+	}
+
+	It("should accept dead letter queues on sink connectors", func() {
+		report := v.Validate(connector(validator.JDBCSinkClass, map[string]string{
+			"errors.tolerance":                                "all",
+			"errors.deadletterqueue.topic.name":               "orders-dlq",
+			"errors.deadletterqueue.topic.replication.factor": "3",
+			"errors.deadletterqueue.context.headers.enable":   "true",
+			"errors.log.enable":                               "true",
+			"errors.log.include.messages":                     "true",
+		}))
+		Expect(report.Violations).To(BeEmpty())
+	})
+
+	It("should warn about dead letter queues without tolerance", func() {
+		report := v.Validate(connector(validator.JDBCSinkClass, map[string]string{
+			"errors.tolerance":                  "none",
+			"errors.deadletterqueue.topic.name": "orders-dlq",
+		}))
+		Expect(report.Valid()).To(BeTrue(), report.Text())
+		Expect(report.Warnings).To(ConsistOf(validator.Violation{
+			Key:     "errors.deadletterqueue.topic.name",
+			Rule:    "deadletterqueue.tolerance",
+			Value:   "orders-dlq",
+			Message: "'errors.deadletterqueue.topic.name' is unused unless 'errors.tolerance' is all",
+		}))
+	})
+
+	DescribeTable("should reject invalid combinations",
+		func(class string, settings map[string]string, message string) {
+			report := v.Validate(connector(class, settings))
+			Expect(report.Violations).To(HaveLen(1))
+			Expect(report.Violations[0].Message).To(Equal(message))
+		},
+		Entry("dead letter queue on a source", validator.JDBCSourceClass,
+			map[string]string{"errors.tolerance": "all", "errors.deadletterqueue.topic.name": "orders-dlq"},
+			"'errors.deadletterqueue.topic.name' is only supported by sink connectors"),
+		Entry("dead letter queue on an unknown source", "com.example.OrdersSourceConnector",
+			map[string]string{"errors.deadletterqueue.context.headers.enable": "true"},
+			"'errors.deadletterqueue.context.headers.enable' is only supported by sink connectors"),
+		Entry("dead letter queue settings without a topic", "com.example.OrdersSinkConnector",
+			map[string]string{"errors.tolerance": "all", "errors.deadletterqueue.topic.replication.factor": "3"},
+			"'errors.deadletterqueue.topic.replication.factor' requires 'errors.deadletterqueue.topic.name'"),
+		Entry("messages logged without the error log", validator.JDBCSinkClass,
+			map[string]string{"errors.log.include.messages": "true"},
+			"'errors.log.include.messages' requires 'errors.log.enable' to be true"),
+		Entry("unknown tolerance", "com.example.Orders",
+			map[string]string{"errors.tolerance": "some"},
+			"Invalid value for 'errors.tolerance': must be one of none, all"),
+		Entry("retry timeout below -1", validator.JDBCSinkClass,
+			map[string]string{"errors.retry.timeout": "-2"},
+			"Invalid value for 'errors.retry.timeout': must be at least -1"),
+		Entry("Avro converter without a schema registry", validator.JDBCSourceClass,
+			map[string]string{"value.converter": "io.confluent.connect.avro.AvroConverter"},
+			"Missing configuration 'value.converter.schema.registry.url', required when 'value.converter' is io.confluent.connect.avro.AvroConverter"),
+		Entry("JSON converter with an invalid schemas.enable", validator.JDBCSinkClass,
+			map[string]string{"key.converter": "org.apache.kafka.connect.json.JsonConverter", "key.converter.schemas.enable": "yes"},
+			"Invalid value for 'key.converter.schemas.enable': must be true or false"),
+	)
+})
@@ -0,0 +1,74 @@
+package validator
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// NamePolicy decides which connector names are accepted, by the Validator and by
+// kafkaconnect.Client. Kafka Connect itself accepts any non-empty name without control
+// characters, so a policy is about conventions: names are escaped when used in URLs.
+type NamePolicy struct {
+	// Pattern is a regular expression names must match. Any name is accepted when nil.
+	Pattern *regexp.Regexp
+	// MaxLength bounds the length of names in bytes. Unbounded when zero.
+	MaxLength int
+}
+
+// ConnectNamePolicy accepts every name Kafka Connect accepts.
+var ConnectNamePolicy = NamePolicy{}
+
+// DefaultNamePolicy is ConnectNamePolicy: names are escaped rather than rejected.
+var DefaultNamePolicy = ConnectNamePolicy
+
+// StrictNamePolicy accepts up to 255 letters, digits, '.', '_' and '-', which covers
+// names derived from topics such as _ims.logs-sink and never need escaping.
+var StrictNamePolicy = NamePolicy{
+	Pattern:   regexp.MustCompile(`^[A-Za-z0-9._-]+$`),
+	MaxLength: 255,
+}
+
+// reason returns why name is rejected, or an empty string when it is accepted.
+func (p NamePolicy) reason(name string) string {
+	if strings.TrimSpace(name) == "" {
+		return "must not be empty"
+	}
+	for _, r := range name {
+		if unicode.IsControl(r) {
+			return "must not contain control characters"
+		}
+	}
+	if p.MaxLength > 0 && len(name) > p.MaxLength {
+		return fmt.Sprintf("must not be longer than %d bytes", p.MaxLength)
+	}
+	if p.Pattern != nil && !p.Pattern.MatchString(name) {
+		return fmt.Sprintf("must match %s", p.Pattern.String())
+	}
+	return ""
+}
+
+// Valid returns true when name is accepted.
+func (p NamePolicy) Valid(name string) bool {
+	return p.reason(name) == ""
+}
+
+// Check returns an error describing why name is rejected, or nil.
+func (p NamePolicy) Check(name string) error {
+	if reason := p.reason(name); reason != "" {
+		return fmt.Errorf("Invalid connector name %q: %s", name, reason)
+	}
+	return nil
+}
+
+// EscapeName escapes a connector name for use as a segment of a URL path, e.g. a name
+// containing '/' or '?'.
+func EscapeName(name string) string {
+	if name == "." || name == ".." {
+		// Dot segments would be removed from the path by clients and servers.
+		return strings.Repeat("%2E", len(name))
+	}
+	return url.PathEscape(name)
+}
@@ -0,0 +1,112 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Violation is a failed check of a connector config.
+type Violation struct {
+	// Key is the config key at fault. Rules involving several keys report the first one.
+	Key string `json:"key"`
+	// Rule is the failed check, e.g. "required", "type", "enum" or a validation tag.
+	Rule string `json:"rule"`
+	// Value is the offending value, replaced by the placeholder of WithSensitiveKeys for
+	// sensitive keys.
+	Value   string `json:"value,omitempty"`
+	Message string `json:"message"`
+}
+
+// String renders the violation on a single line.
+func (v Violation) String() string {
+	if v.Key == "" {
+		return fmt.Sprintf("(%s) %s", v.Rule, v.Message)
+	}
+	return fmt.Sprintf("%s (%s): %s", v.Key, v.Rule, v.Message)
+}
+
+// Report lists every violation found in a connector config. A report with violations is
+// an error; warnings alone do not make it one.
+type Report struct {
+	Connector  string      `json:"connector,omitempty"`
+	Violations []Violation `json:"violations"`
+	Warnings   []Violation `json:"warnings,omitempty"`
+}
+
+// Valid returns true when the config has no violation.
+func (r *Report) Valid() bool {
+	return len(r.Violations) == 0
+}
+
+// Err returns the report as an error, or nil when the config is valid.
+func (r *Report) Err() error {
+	if r.Valid() {
+		return nil
+	}
+	return r
+}
+
+// ByKey returns the violations of key.
+func (r *Report) ByKey(key string) []Violation {
+	var violations []Violation
+	for _, v := range r.Violations {
+		if v.Key == key {
+			violations = append(violations, v)
+		}
+	}
+	return violations
+}
+
+// Error summarizes the report on a single line.
+func (r *Report) Error() string {
+	messages := make([]string, 0, len(r.Violations))
+	for _, v := range r.Violations {
+		messages = append(messages, v.Message)
+	}
+	prefix := "Invalid connector config"
+	if r.Connector != "" {
+		prefix = fmt.Sprintf("Invalid config for connector %s", r.Connector)
+	}
+	return fmt.Sprintf("%s: %s", prefix, strings.Join(messages, "; "))
+}
+
+// Text renders the report for humans, one violation per line.
+func (r *Report) Text() string {
+	var b strings.Builder
+	name := "Connector config"
+	if r.Connector != "" {
+		name = "Connector " + r.Connector
+	}
+	if r.Valid() {
+		fmt.Fprintf(&b, "%s is valid\n", name)
+	} else {
+		fmt.Fprintf(&b, "%s has %d violation(s):\n", name, len(r.Violations))
+		writeViolations(&b, r.Violations)
+	}
+	if len(r.Warnings) > 0 {
+		fmt.Fprintf(&b, "%d warning(s):\n", len(r.Warnings))
+		writeViolations(&b, r.Warnings)
+	}
+	return b.String()
+}
+
+func writeViolations(b *strings.Builder, violations []Violation) {
+	for _, v := range violations {
+		fmt.Fprintf(b, "  %s\n", v.String())
+		if v.Value != "" {
+			fmt.Fprintf(b, "    value: %q\n", v.Value)
+		}
+	}
+}
+
+// JSON renders the report as JSON.
+func (r *Report) JSON() ([]byte, error) {
+	if r.Violations == nil {
+		// Render an empty list rather than null.
+		valid := *r
+		valid.Violations = []Violation{}
+		return json.Marshal(valid)
+	}
+	return json.Marshal(r)
+}
@@ -0,0 +1,158 @@
+package validator_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/walmartdigital/go-kaya/pkg/validator"
+)
+
+var _ = Describe("Report tests", func() {
+	var (
+		v      *validator.Validator
+		config map[string]string
+	)
+
+	BeforeEach(func() {
+		v = validator.New()
+		config = map[string]string{
+			"name":                "logs-sink",
+			"connector.class":     validator.ElasticsearchSinkClass,
+			"connection.password": "hunter2",
+			"topics":              "logs",
+			"tasks.max":           "0",
+			"key.ignore":          "maybe",
+			"topics.regex":        "logs-.*",
+		}
+	})
+
+	It("should report every violation", func() {
+		report := v.Validate(config)
+		Expect(report.Valid()).To(BeFalse())
+		Expect(report.Connector).To(Equal("logs-sink"))
+		Expect(report.Violations).To(ConsistOf(
+			validator.Violation{Key: "connection.url", Rule: "required", Message: "Missing required configuration 'connection.url'"},
+			validator.Violation{Key: "key.ignore", Rule: "type", Value: "maybe", Message: "Invalid value for 'key.ignore': must be true or false"},
+			validator.Violation{Key: "tasks.max", Rule: "min", Value: "0", Message: "Invalid value for 'tasks.max': must be at least 1"},
+			validator.Violation{Key: "topics", Rule: "topics", Value: "logs", Message: "Only one of 'topics' or 'topics.regex' may be set"},
+		))
+		Expect(report.ByKey("tasks.max")).To(HaveLen(1))
+		Expect(report.ByKey("connection.password")).To(BeEmpty())
+	})
+
+	It("should return the report as an error", func() {
+		ok, err := v.ValidateMap(config)
+		Expect(ok).To(BeFalse())
+		report, isReport := err.(*validator.Report)
+		Expect(isReport).To(BeTrue())
+		Expect(report.Violations).To(HaveLen(4))
+		Expect(err.Error()).To(HavePrefix("Invalid config for connector logs-sink: Missing required configuration 'connection.url'; "))
+	})
+
+	It("should return no error for a valid config", func() {
+		config["connection.url"] = "http://es:9200"
+		config["tasks.max"] = "1"
+		config["key.ignore"] = "false"
+		delete(config, "topics.regex")
+		report := v.Validate(config)
+		Expect(report.Valid()).To(BeTrue())
+		Expect(report.Err()).To(BeNil())
+		Expect(report.Text()).To(Equal("Connector logs-sink is valid\n"))
+
+		out, err := report.JSON()
+		Expect(err).To(BeNil())
+		Expect(string(out)).To(Equal(`{"connector":"logs-sink","violations":[]}`))
+	})
+
+	It("should redact sensitive values", func() {
+		registry := validator.NewRegistry()
+		registry.Register(validator.Schema{
+			Class: "com.example.VaultSinkConnector",
+			Fields: map[string]validator.Field{
+				"vault.token":     {Type: validator.Password, Pattern: `^s\.`},
+				"client.secret":   {Pattern: `^[0-9a-f]+$`},
+				"client.audience": {Pattern: `^[0-9a-f]+$`},
+			},
+		})
+		v = validator.New(validator.WithRegistry(registry))
+		report := v.Validate(map[string]string{
+			"name":            "vault",
+			"connector.class": "com.example.VaultSinkConnector",
+			"vault.token":     "hvs.token",
+			"client.secret":   "not-hex",
+			"client.audience": "not-hex",
+		})
+		Expect(report.ByKey("vault.token")[0].Value).To(BeEmpty())
+		Expect(report.ByKey("client.secret")[0].Value).To(Equal("not-hex"))
+		Expect(report.Text()).NotTo(ContainSubstring("hvs.token"))
+
+		v = validator.New(validator.WithRegistry(registry), validator.WithSensitiveKeys(func(key string) bool {
+			return key == "client.audience"
+		}, "[hidden]"))
+		report = v.Validate(map[string]string{
+			"name":            "vault",
+			"connector.class": "com.example.VaultSinkConnector",
+			"vault.token":     "hvs.token",
+			"client.secret":   "not-hex",
+			"client.audience": "not-hex",
+		})
+		Expect(report.ByKey("vault.token")[0].Value).To(Equal("[hidden]"))
+		Expect(report.ByKey("client.secret")[0].Value).To(Equal("not-hex"))
+		Expect(report.ByKey("client.audience")[0].Value).To(Equal("[hidden]"))
+	})
+
+	It("should render the report as text", func() {
+		v = validator.New(validator.WithNamePolicy(validator.StrictNamePolicy))
+		config["name"] = "logs sink"
+		Expect(v.Validate(config).Text()).To(Equal("Connector logs sink has 1 violation(s):\n" +
+			"  name (connectorname): Invalid value for 'name': must match ^[A-Za-z0-9._-]+$\n" +
+			"    value: \"logs sink\"\n"))
+	})
+
+	It("should render the report as JSON", func() {
+		out, err := v.Validate(config).JSON()
+		Expect(err).To(BeNil())
+
+		var decoded map[string]interface{}
+		Expect(json.Unmarshal(out, &decoded)).To(Succeed())
+		Expect(decoded["connector"]).To(Equal("logs-sink"))
+		Expect(decoded["violations"]).To(ContainElement(map[string]interface{}{
+			"key":     "tasks.max",
+			"rule":    "min",
+			"value":   "0",
+			"message": "Invalid value for 'tasks.max': must be at least 1",
+		}))
+		Expect(string(out)).NotTo(ContainSubstring("hunter2"))
+	})
+
+	It("should validate concurrently", func() {
+		var wg sync.WaitGroup
+		errs := make([]error, 50)
+		for i := range errs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				c := map[string]string{
+					"name":            fmt.Sprintf("sink-%d", i),
+					"connector.class": validator.ElasticsearchSinkClass,
+					"connection.url":  "http://es:9200",
+					"topics":          "logs",
+					"tasks.max":       fmt.Sprint(i % 2),
+				}
+				_, errs[i] = v.ValidateMap(c)
+			}(i)
+		}
+		wg.Wait()
+
+		for i, err := range errs {
+			if i%2 == 0 {
+				Expect(err).To(MatchError(fmt.Sprintf("Invalid config for connector sink-%d: Invalid value for 'tasks.max': must be at least 1", i)))
+			} else {
+				Expect(err).To(BeNil())
+			}
+		}
+	})
+})
@@ -0,0 +1,245 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/go-playground/validator.v9"
+)
+
+// Type is the type of a config value, mirroring Kafka's ConfigDef.Type.
+type Type string
+
+// Config value types.
+const (
+	String   Type = "string"
+	Int      Type = "int"
+	Long     Type = "long"
+	Double   Type = "double"
+	Boolean  Type = "boolean"
+	List     Type = "list"
+	Class    Type = "class"
+	Password Type = "password"
+)
+
+var classPattern = regexp.MustCompile(`^([A-Za-z_$][A-Za-z0-9_$]*\.)*[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// placeholderPattern matches values that are entirely a config provider reference, such as
+// ${env:ES_PASSWORD}, which can only be checked once resolved by the worker.
+var placeholderPattern = regexp.MustCompile(`^\$\{[^}]*\}$`)
+
+// Field describes the rules applying to a single config key.
+type Field struct {
+	Required bool
+	Type     Type
+	// Enum lists the accepted values, compared case-insensitively like Kafka does.
+	Enum []string
+	// Pattern is a regular expression the value must match.
+	Pattern string
+	// Tag is a validation registered on the Validator, e.g. "topiclist" or "esdoctype".
+	Tag string
+	// Min and Max bound numeric values.
+	Min *int64
+	Max *int64
+}
+
+// Int64 returns a pointer to n, for Field.Min and Field.Max.
+func Int64(n int64) *int64 {
+	return &n
+}
+
+// Rule is a check involving several config keys. Check returns a description of the
+// problem, or an empty string when the config satisfies the rule.
+type Rule struct {
+	Name string
+	// Keys are the config keys the rule is about, the first one being reported.
+	Keys  []string
+	Check func(config map[string]string) string
+	// Warning rules flag configs Kafka Connect accepts but which are likely mistakes. They
+	// are reported as warnings, which do not make the config invalid.
+	Warning bool
+}
+
+// Schema is the rule set of a connector class.
+type Schema struct {
+	// Class is the fully qualified connector class. Configs using the simple class name
+	// also match, as Kafka Connect accepts both.
+	Class  string
+	Fields map[string]Field
+	Rules  []Rule
+}
+
+// check validates config against the schema and returns the violations and warnings. Tags
+// are evaluated with v, and the values of the keys for which sensitive returns true are
+// redacted.
+func (s *Schema) check(config map[string]string, v *validator.Validate, sensitive func(string) bool, placeholder string) ([]Violation, []Violation) {
+	var violations, warnings []Violation
+
+	keys := make([]string, 0, len(s.Fields))
+	for k := range s.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	redact := func(key string) string {
+		value := config[key]
+		if value != "" && (s.Fields[key].Type == Password || sensitive(key)) {
+			return placeholder
+		}
+		return value
+	}
+
+	for _, key := range keys {
+		f := s.Fields[key]
+		value, ok := config[key]
+		if !ok || value == "" {
+			if f.Required {
+				violations = append(violations, Violation{Key: key, Rule: "required", Message: fmt.Sprintf("Missing required configuration '%s'", key)})
+			}
+			continue
+		}
+		if placeholderPattern.MatchString(value) {
+			continue
+		}
+		if rule, msg := f.check(value, v); rule != "" {
+			violations = append(violations, Violation{Key: key, Rule: rule, Value: redact(key), Message: fmt.Sprintf("Invalid value for '%s': %s", key, msg)})
+		}
+	}
+
+	for _, r := range s.Rules {
+		if msg := r.Check(config); msg != "" {
+			key := ""
+			if len(r.Keys) > 0 {
+				key = r.Keys[0]
+			}
+			violation := Violation{Key: key, Rule: r.Name, Value: redact(key), Message: msg}
+			if r.Warning {
+				warnings = append(warnings, violation)
+			} else {
+				violations = append(violations, violation)
+			}
+		}
+	}
+	return violations, warnings
+}
+
+// check returns the name of the first failed rule and a description of the problem.
+func (f Field) check(value string, v *validator.Validate) (string, string) {
+	if msg := f.checkType(value); msg != "" {
+		return "type", msg
+	}
+	if len(f.Enum) > 0 {
+		found := false
+		for _, e := range f.Enum {
+			found = found || strings.EqualFold(e, value)
+		}
+		if !found {
+			return "enum", fmt.Sprintf("must be one of %s", strings.Join(f.Enum, ", "))
+		}
+	}
+	if f.Pattern != "" {
+		if ok, _ := regexp.MatchString(f.Pattern, value); !ok {
+			return "pattern", fmt.Sprintf("must match %s", f.Pattern)
+		}
+	}
+	if f.Tag != "" && v.Var(value, f.Tag) != nil {
+		return f.Tag, fmt.Sprintf("failed the %s validation", f.Tag)
+	}
+	if f.Min != nil || f.Max != nil {
+		n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return "type", "must be an integer"
+		}
+		if f.Min != nil && n < *f.Min {
+			return "min", fmt.Sprintf("must be at least %d", *f.Min)
+		}
+		if f.Max != nil && n > *f.Max {
+			return "max", fmt.Sprintf("must be at most %d", *f.Max)
+		}
+	}
+	return "", ""
+}
+
+func (f Field) checkType(value string) string {
+	value = strings.TrimSpace(value)
+	switch f.Type {
+	case Int:
+		if _, err := strconv.ParseInt(value, 10, 32); err != nil {
+			return "must be an int"
+		}
+	case Long:
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return "must be a long"
+		}
+	case Double:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return "must be a double"
+		}
+	case Boolean:
+		if !strings.EqualFold(value, "true") && !strings.EqualFold(value, "false") {
+			return "must be true or false"
+		}
+	case Class:
+		if !classPattern.MatchString(value) {
+			return "must be a Java class name"
+		}
+	}
+	return ""
+}
+
+// Registry maps connector classes to their schemas. It is safe for concurrent use.
+type Registry struct {
+	mu      sync.RWMutex
+	schemas map[string]*Schema
+}
+
+// NewRegistry returns a registry holding the built-in schemas.
+func NewRegistry() *Registry {
+	r := &Registry{schemas: map[string]*Schema{}}
+	for _, s := range builtinSchemas() {
+		r.Register(s)
+	}
+	return r
+}
+
+// DefaultRegistry is used by validators created with New.
+var DefaultRegistry = NewRegistry()
+
+// Register adds the schema of a connector class, replacing any previous one.
+func (r *Registry) Register(s Schema) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.schemas[s.Class] = &s
+}
+
+// Lookup returns the schema of a connector class, given by its fully qualified or simple
+// name.
+func (r *Registry) Lookup(class string) (*Schema, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if s, ok := r.schemas[class]; ok {
+		return s, true
+	}
+	for name, s := range r.schemas {
+		if strings.HasSuffix(name, "."+class) {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+// Classes returns the connector classes having a schema.
+func (r *Registry) Classes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	classes := make([]string, 0, len(r.schemas))
+	for c := range r.schemas {
+		classes = append(classes, c)
+	}
+	sort.Strings(classes)
+	return classes
+}
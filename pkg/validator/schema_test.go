@@ -0,0 +1,270 @@
+package validator_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/walmartdigital/go-kaya/pkg/validator"
+)
+
+var _ = Describe("Schema tests", func() {
+	var (
+		v *validator.Validator
+	)
+
+	BeforeEach(func() {
+		v = validator.New()
+	})
+
+	esSink := func() map[string]string {
+		return map[string]string{
+			"name":            "logs-sink",
+			"connector.class": validator.ElasticsearchSinkClass,
+			"connection.url":  "http://es-0:9200,http://es-1:9200",
+			"topics":          "logs,audit",
+			"topic.index.map": "logs:<logs-{now/d}>,audit:audit",
+			"type.name":       "_doc",
+			"tasks.max":       "2",
+			"key.ignore":      "true",
+		}
+	}
+
+	It("should have built-in schemas for common connectors", func() {
+		Expect(validator.DefaultRegistry.Classes()).To(ConsistOf(
+			validator.ElasticsearchSinkClass,
+			validator.JDBCSinkClass,
+			validator.JDBCSourceClass,
+			validator.S3SinkClass,
+			validator.DebeziumMySQLClass,
+		))
+	})
+
+	It("should look up a schema by its simple class name", func() {
+		s, ok := validator.DefaultRegistry.Lookup("ElasticsearchSinkConnector")
+		Expect(ok).To(BeTrue())
+		Expect(s.Class).To(Equal(validator.ElasticsearchSinkClass))
+
+		_, ok = validator.DefaultRegistry.Lookup("SinkConnector")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should accept a valid Elasticsearch sink config", func() {
+		ok, err := v.ValidateMap(esSink())
+		Expect(err).To(BeNil())
+		Expect(ok).To(BeTrue())
+	})
+
+	DescribeTable("should reject an invalid Elasticsearch sink config",
+		func(key string, value string, message string) {
+			config := esSink()
+			if value == "" {
+				delete(config, key)
+			} else {
+				config[key] = value
+			}
+			ok, err := v.ValidateMap(config)
+			Expect(ok).To(BeFalse())
+			Expect(err).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("missing connection.url", "connection.url", "", "Missing required configuration 'connection.url'"),
+		Entry("connection.url not a URL", "connection.url", "es-0:9200", "Invalid value for 'connection.url'"),
+		Entry("tasks.max not an int", "tasks.max", "two", "Invalid value for 'tasks.max': must be an int"),
+		Entry("tasks.max below 1", "tasks.max", "0", "Invalid value for 'tasks.max': must be at least 1"),
+		Entry("key.ignore not a boolean", "key.ignore", "yes", "Invalid value for 'key.ignore': must be true or false"),
+		Entry("unknown behavior", "behavior.on.null.values", "drop", "must be one of ignore, delete, fail"),
+		Entry("invalid topic list", "topics", "logs,,audit", "failed the topiclist validation"),
+		Entry("invalid topic index map", "topic.index.map", "logs<logs>", "failed the topicindexmap validation"),
+		Entry("invalid document type", "type.name", "doc type", "failed the esdoctype validation"),
+		Entry("missing topics", "topics", "", "One of 'topics' or 'topics.regex' is required"),
+		Entry("both topics and topics.regex", "topics.regex", "logs-.*", "Only one of 'topics' or 'topics.regex' may be set"),
+	)
+
+	It("should not check config provider references", func() {
+		config := esSink()
+		config["tasks.max"] = "${env:TASKS}"
+		ok, err := v.ValidateMap(config)
+		Expect(err).To(BeNil())
+		Expect(ok).To(BeTrue())
+	})
+
+	It("should apply cross-field rules of the JDBC sink", func() {
+		config := map[string]string{
+			"name":            "orders-sink",
+			"connector.class": validator.JDBCSinkClass,
+			"connection.url":  "jdbc:postgresql://db:5432/orders",
+			"topics":          "orders",
+			"insert.mode":     "upsert",
+		}
+		ok, err := v.ValidateMap(config)
+		Expect(ok).To(BeFalse())
+		Expect(err).To(MatchError(ContainSubstring("'pk.mode' must be set when 'insert.mode' is upsert")))
+
+		config["pk.mode"] = "record_key"
+		ok, err = v.ValidateMap(config)
+		Expect(err).To(BeNil())
+		Expect(ok).To(BeTrue())
+
+		config["delete.enabled"] = "true"
+		config["pk.mode"] = "kafka"
+		ok, err = v.ValidateMap(config)
+		Expect(ok).To(BeFalse())
+		Expect(err).To(MatchError(ContainSubstring("'pk.mode' must be record_key when 'delete.enabled' is true")))
+	})
+
+	It("should apply cross-field rules of the JDBC source", func() {
+		config := map[string]string{
+			"name":            "orders-source",
+			"connector.class": validator.JDBCSourceClass,
+			"connection.url":  "jdbc:mysql://db:3306/orders",
+			"mode":            "incrementing",
+			"topic.prefix":    "mysql-",
+		}
+		ok, err := v.ValidateMap(config)
+		Expect(ok).To(BeFalse())
+		Expect(err).To(MatchError(ContainSubstring("Missing configuration 'incrementing.column.name', required when 'mode' is incrementing")))
+
+		config["incrementing.column.name"] = "id"
+		config["query"] = "SELECT * FROM orders"
+		config["table.whitelist"] = "orders"
+		ok, err = v.ValidateMap(config)
+		Expect(ok).To(BeFalse())
+		Expect(err).To(MatchError(ContainSubstring("Only one of 'query', 'table.whitelist' may be set")))
+	})
+
+	It("should validate S3 sink configs", func() {
+		config := map[string]string{
+			"name":              "archive",
+			"connector.class":   validator.S3SinkClass,
+			"topics":            "logs",
+			"s3.bucket.name":    "kaya-archive",
+			"s3.region":         "us-east-1",
+			"flush.size":        "1000",
+			"storage.class":     "io.confluent.connect.s3.storage.S3Storage",
+			"format.class":      "io.confluent.connect.s3.format.json.JsonFormat",
+			"partitioner.class": "io.confluent.connect.storage.partitioner.TimeBasedPartitioner",
+		}
+		ok, err := v.ValidateMap(config)
+		Expect(ok).To(BeFalse())
+		Expect(err).To(MatchError(ContainSubstring("Missing configuration 'path.format', required by the TimeBasedPartitioner")))
+
+		config["partitioner.class"] = "io.confluent.connect.storage.partitioner.DefaultPartitioner"
+		ok, err = v.ValidateMap(config)
+		Expect(err).To(BeNil())
+		Expect(ok).To(BeTrue())
+
+		config["format.class"] = "json format"
+		ok, err = v.ValidateMap(config)
+		Expect(ok).To(BeFalse())
+		Expect(err).To(MatchError(ContainSubstring("Invalid value for 'format.class': must be a Java class name")))
+	})
+
+	It("should validate Debezium MySQL configs", func() {
+		config := map[string]string{
+			"name":                 "inventory",
+			"connector.class":      validator.DebeziumMySQLClass,
+			"tasks.max":            "1",
+			"database.hostname":    "mysql",
+			"database.port":        "3306",
+			"database.user":        "debezium",
+			"database.password":    "${file:/secrets/mysql.properties:password}",
+			"database.server.id":   "184054",
+			"database.server.name": "dbserver1",
+			"database.history.kafka.bootstrap.servers": "kafka:9092",
+			"database.history.kafka.topic":             "dbhistory.inventory",
+		}
+		ok, err := v.ValidateMap(config)
+		Expect(err).To(BeNil())
+		Expect(ok).To(BeTrue())
+
+		config["tasks.max"] = "2"
+		report := v.Validate(config)
+		Expect(report.Valid()).To(BeTrue())
+		Expect(report.Warnings).To(HaveLen(1))
+		Expect(report.Warnings[0].Key).To(Equal("tasks.max"))
+
+		config["topic.prefix"] = "dbserver1"
+		ok, err = v.ValidateMap(config)
+		Expect(ok).To(BeFalse())
+		Expect(err).To(MatchError(ContainSubstring("Only one of 'database.server.name', 'database.history.kafka.bootstrap.servers', 'database.history.kafka.topic' or 'topic.prefix'")))
+	})
+
+	It("should validate Debezium 2.x MySQL configs", func() {
+		config := map[string]string{
+			"name":               "inventory",
+			"connector.class":    validator.DebeziumMySQLClass,
+			"database.hostname":  "mysql",
+			"database.user":      "debezium",
+			"database.server.id": "184054",
+			"topic.prefix":       "dbserver1",
+			"schema.history.internal.kafka.bootstrap.servers": "kafka:9092",
+			"schema.history.internal.kafka.topic":             "schemahistory.inventory",
+		}
+		ok, err := v.ValidateMap(config)
+		Expect(err).To(BeNil())
+		Expect(ok).To(BeTrue())
+
+		delete(config, "schema.history.internal.kafka.topic")
+		ok, err = v.ValidateMap(config)
+		Expect(ok).To(BeFalse())
+		Expect(err).To(MatchError(ContainSubstring("Missing configuration 'schema.history.internal.kafka.topic', required with 'topic.prefix'")))
+
+		delete(config, "topic.prefix")
+		delete(config, "schema.history.internal.kafka.bootstrap.servers")
+		ok, err = v.ValidateMap(config)
+		Expect(ok).To(BeFalse())
+		Expect(err).To(MatchError(ContainSubstring("One of 'database.server.name'")))
+	})
+
+	It("should use custom schemas", func() {
+		registry := validator.NewRegistry()
+		registry.Register(validator.Schema{
+			Class: "com.example.HTTPSinkConnector",
+			Fields: map[string]validator.Field{
+				"http.api.url":   {Required: true, Pattern: `^https://`},
+				"request.method": {Enum: []string{"POST", "PUT"}},
+			},
+			Rules: []validator.Rule{{
+				Name: "auth",
+				Keys: []string{"auth.password"},
+				Check: func(config map[string]string) string {
+					if config["auth.password"] != "" && config["auth.username"] == "" {
+						return "'auth.username' is required with 'auth.password'"
+					}
+					return ""
+				},
+			}},
+		})
+		v = validator.New(validator.WithRegistry(registry))
+
+		config := map[string]string{
+			"name":            "webhook",
+			"connector.class": "com.example.HTTPSinkConnector",
+			"http.api.url":    "https://example.com/hook",
+			"request.method":  "put",
+		}
+		ok, err := v.ValidateMap(config)
+		Expect(err).To(BeNil())
+		Expect(ok).To(BeTrue())
+
+		config["http.api.url"] = "http://example.com/hook"
+		ok, err = v.ValidateMap(config)
+		Expect(ok).To(BeFalse())
+		Expect(err).To(MatchError(ContainSubstring("Invalid value for 'http.api.url': must match ^https://")))
+
+		config["http.api.url"] = "https://example.com/hook"
+		config["auth.password"] = "secret"
+		ok, err = v.ValidateMap(config)
+		Expect(ok).To(BeFalse())
+		Expect(err).To(MatchError(ContainSubstring("'auth.username' is required with 'auth.password'")))
+	})
+
+	It("should only check the common fields of unknown classes", func() {
+		config := map[string]string{
+			"name":            "custom",
+			"connector.class": "com.example.UnknownConnector",
+		}
+		ok, err := v.ValidateMap(config)
+		Expect(err).To(BeNil())
+		Expect(ok).To(BeTrue())
+	})
+})
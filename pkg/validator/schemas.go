@@ -0,0 +1,335 @@
+package validator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Classes of the connectors having a built-in schema.
+const (
+	ElasticsearchSinkClass = "io.confluent.connect.elasticsearch.ElasticsearchSinkConnector"
+	JDBCSinkClass          = "io.confluent.connect.jdbc.JdbcSinkConnector"
+	JDBCSourceClass        = "io.confluent.connect.jdbc.JdbcSourceConnector"
+	S3SinkClass            = "io.confluent.connect.s3.S3SinkConnector"
+	DebeziumMySQLClass     = "io.debezium.connector.mysql.MySqlConnector"
+)
+
+const urlListPattern = `^https?://[^,\s]+(,\s*https?://[^,\s]+)*$`
+
+func builtinSchemas() []Schema {
+	return []Schema{
+		elasticsearchSinkSchema(),
+		jdbcSinkSchema(),
+		jdbcSourceSchema(),
+		s3SinkSchema(),
+		debeziumMySQLSchema(),
+	}
+}
+
+// withCommon adds the fields shared by every connector to fields, unless the connector
+// defines them.
+func withCommon(fields map[string]Field) map[string]Field {
+	return merge(merge(fields, map[string]Field{
+		"tasks.max":       {Type: Int, Min: Int64(1)},
+		"key.converter":   {Type: Class},
+		"value.converter": {Type: Class},
+	}), errorHandlingFields)
+}
+
+// withSinkCommon adds the fields shared by every sink connector to fields, unless the
+// connector defines them.
+func withSinkCommon(fields map[string]Field) map[string]Field {
+	return withCommon(merge(merge(fields, map[string]Field{
+		"topics": {Type: List, Tag: "topiclist"},
+	}), deadLetterQueueFields))
+}
+
+func merge(fields map[string]Field, defaults map[string]Field) map[string]Field {
+	for k, f := range defaults {
+		if _, ok := fields[k]; !ok {
+			fields[k] = f
+		}
+	}
+	return fields
+}
+
+// topicsRule requires a sink connector to consume either topics or topics.regex.
+var topicsRule = Rule{
+	Name: "topics",
+	Keys: []string{"topics", "topics.regex"},
+	Check: func(config map[string]string) string {
+		topics, regex := config["topics"] != "", config["topics.regex"] != ""
+		switch {
+		case !topics && !regex:
+			return "One of 'topics' or 'topics.regex' is required"
+		case topics && regex:
+			return "Only one of 'topics' or 'topics.regex' may be set"
+		}
+		return ""
+	},
+}
+
+// exclusive returns a rule forbidding keys to be set together.
+func exclusive(keys ...string) Rule {
+	return Rule{
+		Name: "exclusive",
+		Keys: keys,
+		Check: func(config map[string]string) string {
+			var set []string
+			for _, k := range keys {
+				if config[k] != "" {
+					set = append(set, k)
+				}
+			}
+			if len(set) > 1 {
+				return fmt.Sprintf("Only one of %s may be set", quote(set))
+			}
+			return ""
+		},
+	}
+}
+
+// oneOf returns a rule requiring the keys of exactly one of groups to be set, e.g. the
+// settings of two major versions of a connector.
+func oneOf(groups ...[]string) Rule {
+	var keys, names []string
+	for _, g := range groups {
+		keys = append(keys, g...)
+		names = append(names, quote(g))
+	}
+	return Rule{
+		Name: "oneof",
+		Keys: keys,
+		Check: func(config map[string]string) string {
+			var used [][]string
+			for _, g := range groups {
+				for _, k := range g {
+					if config[k] != "" {
+						used = append(used, g)
+						break
+					}
+				}
+			}
+			switch len(used) {
+			case 0:
+				return fmt.Sprintf("One of %s is required", strings.Join(names, " or "))
+			case 1:
+				for _, k := range used[0] {
+					if config[k] == "" {
+						return fmt.Sprintf("Missing configuration '%s', required with %s", k, quote(used[0]))
+					}
+				}
+				return ""
+			}
+			return fmt.Sprintf("Only one of %s may be set", strings.Join(names, " or "))
+		},
+	}
+}
+
+func quote(keys []string) string {
+	quoted := make([]string, 0, len(keys))
+	for _, k := range keys {
+		quoted = append(quoted, "'"+k+"'")
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// requiredWhen returns a rule requiring key when the value of other is one of values.
+func requiredWhen(key string, other string, values ...string) Rule {
+	return Rule{
+		Name: "required",
+		Keys: []string{key, other},
+		Check: func(config map[string]string) string {
+			if config[key] != "" {
+				return ""
+			}
+			for _, v := range values {
+				if strings.EqualFold(config[other], v) {
+					return fmt.Sprintf("Missing configuration '%s', required when '%s' is %s", key, other, v)
+				}
+			}
+			return ""
+		},
+	}
+}
+
+func elasticsearchSinkSchema() Schema {
+	return Schema{
+		Class: ElasticsearchSinkClass,
+		Fields: withSinkCommon(map[string]Field{
+			"connection.url":                  {Required: true, Type: List, Pattern: urlListPattern},
+			"connection.username":             {Type: String},
+			"connection.password":             {Type: Password},
+			"type.name":                       {Type: String, Tag: "esdoctype"},
+			"topic.index.map":                 {Type: List, Tag: "topicindexmap"},
+			"key.ignore":                      {Type: Boolean},
+			"schema.ignore":                   {Type: Boolean},
+			"batch.size":                      {Type: Int, Min: Int64(1)},
+			"max.buffered.records":            {Type: Int, Min: Int64(1)},
+			"max.in.flight.requests":          {Type: Int, Min: Int64(1)},
+			"linger.ms":                       {Type: Long, Min: Int64(0)},
+			"flush.timeout.ms":                {Type: Long, Min: Int64(0)},
+			"max.retries":                     {Type: Int, Min: Int64(0)},
+			"retry.backoff.ms":                {Type: Long, Min: Int64(0)},
+			"connection.timeout.ms":           {Type: Int, Min: Int64(0)},
+			"read.timeout.ms":                 {Type: Int, Min: Int64(0)},
+			"write.method":                    {Type: String, Enum: []string{"insert", "upsert"}},
+			"behavior.on.null.values":         {Type: String, Enum: []string{"ignore", "delete", "fail"}},
+			"behavior.on.malformed.documents": {Type: String, Enum: []string{"ignore", "warn", "fail"}},
+		}),
+		Rules: sinkRules(append([]Rule{topicsRule}, elasticsearchRules...)...),
+	}
+}
+
+func jdbcSinkSchema() Schema {
+	return Schema{
+		Class: JDBCSinkClass,
+		Fields: withSinkCommon(map[string]Field{
+			"connection.url":      {Required: true, Type: String, Pattern: `^jdbc:`},
+			"connection.user":     {Type: String},
+			"connection.password": {Type: Password},
+			"insert.mode":         {Type: String, Enum: []string{"insert", "upsert", "update"}},
+			"pk.mode":             {Type: String, Enum: []string{"none", "kafka", "record_key", "record_value"}},
+			"pk.fields":           {Type: List},
+			"auto.create":         {Type: Boolean},
+			"auto.evolve":         {Type: Boolean},
+			"delete.enabled":      {Type: Boolean},
+			"batch.size":          {Type: Int, Min: Int64(1)},
+			"max.retries":         {Type: Int, Min: Int64(0)},
+			"retry.backoff.ms":    {Type: Int, Min: Int64(0)},
+			"table.name.format":   {Type: String},
+		}),
+		Rules: sinkRules(
+			topicsRule,
+			Rule{
+				Name: "pk.mode",
+				Keys: []string{"pk.mode", "insert.mode", "delete.enabled"},
+				Check: func(config map[string]string) string {
+					mode := config["pk.mode"]
+					if strings.EqualFold(config["delete.enabled"], "true") && !strings.EqualFold(mode, "record_key") {
+						return "'pk.mode' must be record_key when 'delete.enabled' is true"
+					}
+					if mode != "" && !strings.EqualFold(mode, "none") {
+						return ""
+					}
+					if insert := config["insert.mode"]; strings.EqualFold(insert, "upsert") || strings.EqualFold(insert, "update") {
+						return fmt.Sprintf("'pk.mode' must be set when 'insert.mode' is %s", insert)
+					}
+					return ""
+				},
+			},
+		),
+	}
+}
+
+func jdbcSourceSchema() Schema {
+	return Schema{
+		Class: JDBCSourceClass,
+		Fields: withCommon(map[string]Field{
+			"connection.url":           {Required: true, Type: String, Pattern: `^jdbc:`},
+			"connection.user":          {Type: String},
+			"connection.password":      {Type: Password},
+			"mode":                     {Required: true, Type: String, Enum: []string{"bulk", "incrementing", "timestamp", "timestamp+incrementing"}},
+			"topic.prefix":             {Required: true, Type: String},
+			"incrementing.column.name": {Type: String},
+			"timestamp.column.name":    {Type: List},
+			"table.whitelist":          {Type: List},
+			"table.blacklist":          {Type: List},
+			"query":                    {Type: String},
+			"poll.interval.ms":         {Type: Int, Min: Int64(1)},
+			"batch.max.rows":           {Type: Int, Min: Int64(1)},
+		}),
+		Rules: sourceRules(
+			requiredWhen("incrementing.column.name", "mode", "incrementing", "timestamp+incrementing"),
+			requiredWhen("timestamp.column.name", "mode", "timestamp", "timestamp+incrementing"),
+			exclusive("table.whitelist", "table.blacklist"),
+			exclusive("query", "table.whitelist"),
+			exclusive("query", "table.blacklist"),
+		),
+	}
+}
+
+func s3SinkSchema() Schema {
+	return Schema{
+		Class: S3SinkClass,
+		Fields: withSinkCommon(map[string]Field{
+			"s3.bucket.name":        {Required: true, Type: String, Pattern: `^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`},
+			"s3.region":             {Type: String, Pattern: `^[a-z]{2}(-[a-z]+)+-\d$`},
+			"flush.size":            {Required: true, Type: Int, Min: Int64(1)},
+			"storage.class":         {Required: true, Type: Class},
+			"format.class":          {Required: true, Type: Class},
+			"partitioner.class":     {Type: Class},
+			"rotate.interval.ms":    {Type: Long},
+			"s3.part.size":          {Type: Int, Min: Int64(5242880)},
+			"topics.dir":            {Type: String},
+			"path.format":           {Type: String},
+			"partition.duration.ms": {Type: Long},
+			"timezone":              {Type: String},
+		}),
+		Rules: sinkRules(
+			topicsRule,
+			Rule{
+				Name: "partitioner.class",
+				Keys: []string{"path.format", "partitioner.class"},
+				Check: func(config map[string]string) string {
+					if !strings.HasSuffix(config["partitioner.class"], ".TimeBasedPartitioner") {
+						return ""
+					}
+					for _, k := range []string{"path.format", "partition.duration.ms", "timezone"} {
+						if config[k] == "" {
+							return fmt.Sprintf("Missing configuration '%s', required by the TimeBasedPartitioner", k)
+						}
+					}
+					return ""
+				},
+			},
+		),
+	}
+}
+
+func debeziumMySQLSchema() Schema {
+	return Schema{
+		Class: DebeziumMySQLClass,
+		Fields: withCommon(map[string]Field{
+			"database.hostname":      {Required: true, Type: String},
+			"database.port":          {Type: Int, Min: Int64(1), Max: Int64(65535)},
+			"database.user":          {Required: true, Type: String},
+			"database.password":      {Type: Password},
+			"database.server.id":     {Required: true, Type: Long, Min: Int64(1)},
+			"database.include.list":  {Type: List},
+			"database.exclude.list":  {Type: List},
+			"table.include.list":     {Type: List},
+			"table.exclude.list":     {Type: List},
+			"snapshot.mode":          {Type: String, Enum: []string{"initial", "initial_only", "when_needed", "never", "schema_only", "schema_only_recovery"}},
+			"include.schema.changes": {Type: Boolean},
+			// Debezium 1.x settings.
+			"database.server.name":                     {Type: String, Pattern: `^[A-Za-z0-9_.-]+$`},
+			"database.history.kafka.bootstrap.servers": {Type: List},
+			"database.history.kafka.topic":             {Type: String, Tag: "kafkatopic"},
+			// Debezium 2.x settings, replacing the ones above.
+			"topic.prefix": {Type: String, Pattern: `^[A-Za-z0-9_.-]+$`},
+			"schema.history.internal.kafka.bootstrap.servers": {Type: List},
+			"schema.history.internal.kafka.topic":             {Type: String, Tag: "kafkatopic"},
+		}),
+		Rules: sourceRules(
+			oneOf(
+				[]string{"database.server.name", "database.history.kafka.bootstrap.servers", "database.history.kafka.topic"},
+				[]string{"topic.prefix", "schema.history.internal.kafka.bootstrap.servers", "schema.history.internal.kafka.topic"},
+			),
+			exclusive("database.include.list", "database.exclude.list"),
+			exclusive("table.include.list", "table.exclude.list"),
+			Rule{
+				Name: "tasks.max",
+				Keys: []string{"tasks.max"},
+				Check: func(config map[string]string) string {
+					if n, err := strconv.Atoi(config["tasks.max"]); err == nil && n > 1 {
+						return "The MySQL connector always runs a single task, 'tasks.max' above 1 has no effect"
+					}
+					return ""
+				},
+				Warning: true,
+			},
+		),
+	}
+}
@@ -9,16 +9,12 @@ import (
 
 var validationMap = map[string]string{
 	"name":            "connectorname",
-	"connector.class": "fqdn",
+	"connector.class": "classname",
 }
 
-func validateConnectorName(name string) bool {
-	r, err := regexp.Compile("^[A-Za-z0-9\\._-]{1,255}$")
-	if err != nil {
-		panic(err)
-	}
-	return r.MatchString(name)
-}
+// classNamePattern matches fully qualified Java class names as well as the simple names
+// and aliases Kafka Connect resolves, e.g. FileStreamSink.
+var classNamePattern = regexp.MustCompile(`^[\p{L}_$][\p{L}\p{N}_$]*(\.[\p{L}_$][\p{L}\p{N}_$]*)*$`)
 
 func validateESDocType(name string) bool {
 	r, err := regexp.Compile("^[A-Za-z0-9_-]{1,63}$")
@@ -52,33 +48,105 @@ func validateTopicIndexMap(name string) bool {
 	return r.MatchString(name)
 }
 
-// Validator ...
+// Validator checks connector configs. It is safe for concurrent use.
 type Validator struct {
+	// LastError is no longer set.
+	//
+	// Deprecated: it was shared by concurrent calls, use Validate or the error returned by
+	// ValidateMap.
 	LastError error
 	validator *validator.Validate
+	registry  *Registry
+	sensitive func(key string) bool
+	redacted  string
+	names     NamePolicy
 }
 
-// ValidateMap ...
-func (v *Validator) ValidateMap(input map[string]string) (bool, error) {
-	err := v.validator.Var(input, "connectorconfigmap")
-	if err != nil {
-		return false, v.LastError
+// Option configures a Validator.
+type Option func(*Validator)
+
+// WithRegistry sets the registry of connector class schemas. Defaults to DefaultRegistry.
+func WithRegistry(registry *Registry) Option {
+	return func(v *Validator) {
+		v.registry = registry
+	}
+}
+
+// WithNamePolicy sets the policy connector names must comply with. Defaults to
+// DefaultNamePolicy.
+func WithNamePolicy(policy NamePolicy) Option {
+	return func(v *Validator) {
+		v.names = policy
+	}
+}
+
+// WithSensitiveKeys sets the function telling which config keys hold secrets and the
+// placeholder replacing their values in reports. Password fields of schemas are always
+// redacted; without this option their values are left out of reports.
+func WithSensitiveKeys(sensitive func(key string) bool, placeholder string) Option {
+	return func(v *Validator) {
+		v.sensitive = sensitive
+		v.redacted = placeholder
+	}
+}
+
+// Validate checks the connector name and class, then the schema registered for the class,
+// or the settings shared by every connector for classes without a schema, and reports every
+// violation.
+func (v *Validator) Validate(config map[string]string) *Report {
+	report := &Report{Connector: config["name"]}
+	for _, k := range []string{"name", "connector.class"} {
+		value, ok := config[k]
+		switch {
+		case !ok || value == "":
+			report.Violations = append(report.Violations, Violation{Key: k, Rule: "required", Message: fmt.Sprintf("Missing required configuration '%s'", k)})
+		case k == "name":
+			if reason := v.names.reason(value); reason != "" {
+				report.Violations = append(report.Violations, Violation{Key: k, Rule: validationMap[k], Value: value, Message: fmt.Sprintf("Invalid value for '%s': %s", k, reason)})
+			}
+		case !classNamePattern.MatchString(value):
+			report.Violations = append(report.Violations, Violation{Key: k, Rule: validationMap[k], Value: value, Message: fmt.Sprintf("Invalid value for '%s': must be a Java class name or alias", k)})
+		}
 	}
-	return true, nil
+	if len(report.Violations) > 0 {
+		// Without a valid class there is no schema to check against.
+		return report
+	}
+
+	schema, ok := v.registry.Lookup(config["connector.class"])
+	if !ok {
+		schema = fallbackSchema(config["connector.class"])
+	}
+	violations, warnings := schema.check(config, v.validator, v.sensitive, v.redacted)
+	report.Violations = append(report.Violations, violations...)
+	report.Warnings = warnings
+	return report
+}
+
+// ValidateMap returns false and the report of Validate as an error when config is invalid.
+func (v *Validator) ValidateMap(input map[string]string) (bool, error) {
+	report := v.Validate(input)
+	return report.Valid(), report.Err()
 }
 
 // New ...
-func New() *Validator {
+func New(opts ...Option) *Validator {
 	var err error
 
 	v := validator.New()
 
 	instance := Validator{
 		validator: v,
+		registry:  DefaultRegistry,
+		sensitive: func(string) bool { return false },
+		names:     DefaultNamePolicy,
+	}
+	for _, opt := range opts {
+		opt(&instance)
 	}
 
 	err = v.RegisterValidation("connectorname", func(fl validator.FieldLevel) bool {
-		return validateConnectorName(fl.Field().String())
+		return instance.names.Valid(fl.Field().String())
 	})
 
 	if err != nil {
@@ -119,19 +187,7 @@ func New() *Validator {
 
 	err = v.RegisterValidation("connectorconfigmap", func(fl validator.FieldLevel) bool {
 		m, ok := fl.Field().Interface().(map[string]string)
-		if !ok {
-			instance.LastError = fmt.Errorf("Not map[string]string")
-			return false
-		}
-
-		for k, val := range validationMap {
-			if v.Var(m[k], val) != nil {
-				instance.LastError = fmt.Errorf("Field '%s' failed validation", k)
-				return false
-			}
-		}
-
-		return true
+		return ok && instance.Validate(m).Valid()
 	})
 
 	if err != nil {
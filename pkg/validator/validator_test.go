@@ -27,7 +27,9 @@ var _ = Describe("Validator tests", func() {
 		config := map[string]string{
 			"name":            "amida.logging",
 			"connector.class": "io.confluent.connect.elasticsearch.ElasticsearchSinkConnector",
-			"topic.index.map": "dumblogger-logs<logs-pd-dumblogger-{now/d}>,_ims.logs:<logs-pd-ims-{now/d}>,_amida.logs:<logs-pd-amida-{now/d}>,_osiris.logs:<logs-pd-osiris-{now/d}>,_midas.logs:<logs-pd-midas-{now/d}>,_kimun.logs:<logs-pd-kimun-{now/d}>",
+			"connection.url":  "http://elasticsearch:9200",
+			"topics":          "dumblogger-logs,_ims.logs,_amida.logs,_osiris.logs,_midas.logs,_kimun.logs",
+			"topic.index.map": "dumblogger-logs:<logs-pd-dumblogger-{now/d}>,_ims.logs:<logs-pd-ims-{now/d}>,_amida.logs:<logs-pd-amida-{now/d}>,_osiris.logs:<logs-pd-osiris-{now/d}>,_midas.logs:<logs-pd-midas-{now/d}>,_kimun.logs:<logs-pd-kimun-{now/d}>",
 		}
 		ok, err := v.ValidateMap(config)
 		Expect(ok).To(Equal(true))
@@ -66,6 +68,16 @@ var _ = Describe("Validator tests", func() {
 		Expect(err).NotTo(BeNil())
 	})
 
+	It("should accept connector class aliases", func() {
+		config := map[string]string{
+			"name":            "orders-sink",
+			"connector.class": "FileStreamSink",
+		}
+		ok, err := v.ValidateMap(config)
+		Expect(ok).To(Equal(true))
+		Expect(err).To(BeNil())
+	})
+
 	It("should fail validation due to a missing connector class", func() {
 		config := map[string]string{
 			"name":            "amida.logging",